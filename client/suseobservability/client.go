@@ -2,7 +2,6 @@ package suseobservability
 
 import (
 	"context"
-	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -17,29 +16,45 @@ import (
 )
 
 type Client struct {
-	soURL    string
-	token    string
-	apiToken bool
+	soURL      string
+	token      string
+	apiToken   bool
+	httpClient *http.Client
 }
 
-var (
-	transport = &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-	}
-)
+// Options configure the connection to SUSE Observability
+type Options struct {
+	TLS TLSConfig
+	// Timeout limits every request, including reading the response. Zero means no limit.
+	Timeout time.Duration
+}
 
-func NewClient(soURL, serviceToken string, apiToken bool) (c *Client, err error) {
+func NewClient(soURL, serviceToken string, apiToken bool, opts Options) (c *Client, err error) {
 	_, err = url.ParseRequestURI(soURL)
 	if err != nil {
 		return
 	}
+	transport, err := newTransport(opts.TLS)
+	if err != nil {
+		return nil, err
+	}
 	c = new(Client)
 	c.soURL, _ = strings.CutSuffix(soURL, "/")
 	c.token = serviceToken
 	c.apiToken = apiToken
+	c.httpClient = &http.Client{Transport: transport, Timeout: opts.Timeout}
 	return
 }
 
+// WithToken returns a copy of the client that authenticates with another token.
+// The copy shares the connections of the client.
+func (c *Client) WithToken(token string, apiToken bool) *Client {
+	clone := *c
+	clone.token = token
+	clone.apiToken = apiToken
+	return &clone
+}
+
 const (
 	GroovyScript   string = "GroovyScript"
 	DefaultTimeout string = "10s"
@@ -115,6 +130,44 @@ func (c Client) ListMetrics(ctx context.Context, start, end time.Time) ([]string
 	return res.Data, nil
 }
 
+// ListMetricLabels fetches the label names of the series matching the selector.
+// An empty selector returns the label names of every series.
+func (c Client) ListMetricLabels(ctx context.Context, selector string, start, end time.Time) ([]string, error) {
+	var res struct {
+		Data []string `json:"data"`
+	}
+	req := c.apiRequests("metrics/labels").
+		Param("start", toMs(start)).
+		Param("end", toMs(end))
+	if selector != "" {
+		req.Param("match[]", selector)
+	}
+	err := req.ToJSON(&res).Fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return res.Data, nil
+}
+
+// ListMetricLabelValues fetches the values of a label for the series matching the selector.
+// An empty selector returns the values across every series.
+func (c Client) ListMetricLabelValues(ctx context.Context, label, selector string, start, end time.Time) ([]string, error) {
+	var res struct {
+		Data []string `json:"data"`
+	}
+	req := c.apiRequests(fmt.Sprintf("metrics/label/%s/values", url.PathEscape(label))).
+		Param("start", toMs(start)).
+		Param("end", toMs(end))
+	if selector != "" {
+		req.Param("match[]", selector)
+	}
+	err := req.ToJSON(&res).Fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return res.Data, nil
+}
+
 // QueryMetric is the instant query at a single point in time.
 // The endpoint evaluates an instant query at a single point in time.
 // Query is the promql query and Time the single point.
@@ -155,7 +208,26 @@ func (c Client) QueryRangeMetric(ctx context.Context, query string, start time.T
 }
 
 func (c Client) SnapShotTopologyQuery(ctx context.Context, query string) ([]ViewComponent, error) {
+	return c.SnapShotTopologyQueryAt(ctx, query, time.Time{})
+}
+
+// SnapShotTopologyQueryAt runs the query against the topology as it was at the given time.
+// A zero time queries the current topology.
+func (c Client) SnapShotTopologyQueryAt(ctx context.Context, query string, at time.Time) ([]ViewComponent, error) {
+	res, err := c.SnapShotTopologyGraph(ctx, query, at)
+	if err != nil {
+		return nil, err
+	}
+	return res.Components, nil
+}
+
+// SnapShotTopologyGraph runs the query at the given time and returns both the components and the relations between them.
+// A zero time queries the current topology.
+func (c Client) SnapShotTopologyGraph(ctx context.Context, query string, at time.Time) (*ViewSnapshotResponse, error) {
 	req := NewViewSnapshotRequest(query)
+	if !at.IsZero() {
+		req.Metadata.QueryTime = at.UnixMilli()
+	}
 	res, err := c.ViewSnapshot(ctx, req)
 	if err != nil {
 		return nil, err
@@ -163,7 +235,7 @@ func (c Client) SnapShotTopologyQuery(ctx context.Context, query string) ([]View
 	if !res.Success {
 		return nil, errors.New(res.Errors[0].Message)
 	}
-	return res.Components, nil
+	return res, nil
 }
 
 func (c Client) ViewSnapshot(ctx context.Context, req *ViewSnapshotRequest) (*ViewSnapshotResponse, error) {
@@ -302,7 +374,7 @@ func (c Client) GetEvent(ctx context.Context, eventId string, startMs int64, end
 
 func (c Client) apiRequests(endpoint string) *rq.Builder {
 	uri := fmt.Sprintf("%s/api/%s", c.soURL, endpoint)
-	return request(uri).
+	return c.request(uri).
 		Header(c.GetXHeader(), c.token)
 }
 
@@ -313,10 +385,10 @@ func (c Client) GetXHeader() string {
 	return "X-API-Key"
 }
 
-func request(uri string) *rq.Builder {
+func (c Client) request(uri string) *rq.Builder {
 	b := rq.URL(uri).
 		ContentType("application/json").
-		Transport(transport)
+		Client(c.httpClient)
 	return b
 }
 
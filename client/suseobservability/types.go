@@ -198,7 +198,8 @@ type querySnapshotResult struct {
 type ViewSnapshotResponse struct {
 	Success    bool `json:"success"`
 	Components []ViewComponent
-	Errors     []*ErrorMsg `json:"errors"`
+	Relations  []ViewRelation `json:"relations"`
+	Errors     []*ErrorMsg    `json:"errors"`
 }
 
 type ViewComponent struct {
@@ -228,6 +229,20 @@ type ViewComponent struct {
 	InternalType      string            `json:"_type"`
 }
 
+type ViewRelation struct {
+	ID                  int64               `json:"id"`
+	Name                string              `json:"name"`
+	Type                int64               `json:"type"`
+	Source              int64               `json:"source"`
+	Target              int64               `json:"target"`
+	DependencyDirection DependencyDirection `json:"dependencyDirection"`
+	LastUpdateTimestamp int64               `json:"lastUpdateTimestamp"`
+	State               struct {
+		HealthState string `json:"healthState"`
+	} `json:"state"`
+	InternalType string `json:"_type"`
+}
+
 type ViewSnapshotRequest struct {
 	Type         string               `json:"_type"`
 	Metadata     ViewSnapshotMetadata `json:"metadata"`
@@ -479,6 +494,41 @@ type TopologyEvent struct {
 	Tags               []EventTag             `json:"tags"`
 }
 
+// DecodeElements splits the untyped event elements into the affected components and relations
+func (e TopologyEvent) DecodeElements() ([]EventComponent, []EventRelation, error) {
+	var components []EventComponent
+	var relations []EventRelation
+	for _, elem := range e.Elements {
+		b, err := json.Marshal(elem)
+		if err != nil {
+			return nil, nil, err
+		}
+		var probe struct {
+			Type   string          `json:"_type"`
+			Source json.RawMessage `json:"source"`
+		}
+		if err := json.Unmarshal(b, &probe); err != nil {
+			return nil, nil, err
+		}
+		// An explicit "source": null is no source, as if the field were absent
+		hasSource := probe.Source != nil && string(probe.Source) != "null"
+		if probe.Type == "EventRelation" || (probe.Type == "" && hasSource) {
+			var r EventRelation
+			if err := json.Unmarshal(b, &r); err != nil {
+				return nil, nil, err
+			}
+			relations = append(relations, r)
+			continue
+		}
+		var c EventComponent
+		if err := json.Unmarshal(b, &c); err != nil {
+			return nil, nil, err
+		}
+		components = append(components, c)
+	}
+	return components, relations, nil
+}
+
 type EventItemsWithTotal struct {
 	Items []TopologyEvent `json:"items"`
 	Total int64           `json:"total"`
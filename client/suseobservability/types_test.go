@@ -0,0 +1,68 @@
+package suseobservability
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestDecodeElements(t *testing.T) {
+	tests := []struct {
+		name           string
+		elements       string
+		wantComponents []int64
+		wantRelations  []int64
+	}{
+		{
+			name:           "typed",
+			elements:       `[{"_type": "EventComponent", "id": 1, "name": "checkout"}, {"_type": "EventRelation", "id": 2, "source": {"id": 1}, "target": {"id": 3}}]`,
+			wantComponents: []int64{1},
+			wantRelations:  []int64{2},
+		},
+		{
+			name:           "untyped",
+			elements:       `[{"id": 1, "name": "checkout"}, {"id": 2, "source": {"id": 1}, "target": {"id": 3}}]`,
+			wantComponents: []int64{1},
+			wantRelations:  []int64{2},
+		},
+		{
+			name:           "untyped with a null source",
+			elements:       `[{"id": 1, "name": "checkout", "source": null}]`,
+			wantComponents: []int64{1},
+		},
+		{
+			name:     "none",
+			elements: `[]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var event TopologyEvent
+			if err := json.Unmarshal([]byte(`{"elements": `+tt.elements+`}`), &event); err != nil {
+				t.Fatal(err)
+			}
+			components, relations, err := event.DecodeElements()
+			if err != nil {
+				t.Fatalf("DecodeElements() failed: %v", err)
+			}
+			if got := componentIDs(components); !slices.Equal(got, tt.wantComponents) {
+				t.Errorf("components = %v, want %v", got, tt.wantComponents)
+			}
+			var gotRelations []int64
+			for _, r := range relations {
+				gotRelations = append(gotRelations, r.ID)
+			}
+			if !slices.Equal(gotRelations, tt.wantRelations) {
+				t.Errorf("relations = %v, want %v", gotRelations, tt.wantRelations)
+			}
+		})
+	}
+}
+
+func componentIDs(components []EventComponent) []int64 {
+	var ids []int64
+	for _, c := range components {
+		ids = append(ids, c.ID)
+	}
+	return ids
+}
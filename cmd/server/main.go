@@ -2,36 +2,187 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"os"
+	"slices"
+	"strings"
 
+	"github.com/google/jsonschema-go/jsonschema"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
 	"suse-observability-mcp/client/suseobservability"
+	"suse-observability-mcp/internal/config"
 	"suse-observability-mcp/internal/tools"
 )
 
 func main() {
-	// SUSE Observability flags
-	url := flag.String("url", "", "SUSE Observability API URL")
-	token := flag.String("token", "", "SUSE Observability API Token")
-	useAPIToken := flag.Bool("apitoken", false, "Indicates if the token is an API token, instead of a service token")
+	cfg, err := config.Load(os.Args[1:], os.Getenv)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err == nil {
+		err = validateTools(cfg)
+	}
+	if err != nil {
+		slog.Error("Invalid configuration", "error", err)
+		os.Exit(1)
+	}
+
+	var instances []tools.Instance
+	for _, instance := range cfg.AllInstances() {
+		client, err := suseobservability.NewClient(instance.URL, instance.Token, instance.APIToken, suseobservability.Options{
+			TLS: suseobservability.TLSConfig{
+				CAFile:             instance.TLS.CA,
+				CertFile:           instance.TLS.Cert,
+				KeyFile:            instance.TLS.Key,
+				InsecureSkipVerify: instance.TLS.Insecure,
+			},
+			Timeout: cfg.Timeouts.Request,
+		})
+		if err != nil {
+			slog.Error("Failed to create SUSE Observability client", "instance", instance.Name, "error", err)
+			os.Exit(1)
+		}
+		instances = append(instances, tools.Instance{Name: instance.Name, Client: client})
+	}
 
-	// MCP server flags
-	listenAddr := flag.String("http", "", "address for http transport, defaults to stdio")
-	flag.Parse()
+	if cfg.HTTP == "" {
+		// Run the server on the stdio transport.
+		if err := newServer(instances, cfg, nil).Run(context.Background(), &mcp.StdioTransport{}); err != nil {
+			slog.Error("Server failed", "error", err)
+		}
+	} else {
+		useOAuth := cfg.OAuth.Enabled()
 
-	client, err := suseobservability.NewClient(*url, *token, *useAPIToken)
-	if err != nil {
-		return
+		// Create a streamable HTTP handler.
+		var handler http.Handler
+		if cfg.TokenPassthrough {
+			handler = newTokenPassthrough(instances[0], cfg, !useOAuth).handler()
+		} else {
+			mcpServer := newServer(instances, cfg, nil)
+			handler = mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server {
+				return mcpServer
+			}, nil)
+		}
+
+		if useOAuth {
+			oauth := oauthConfig{
+				Resource: cfg.OAuth.Resource,
+				Issuer:   cfg.OAuth.Issuer,
+				JWKSURL:  cfg.OAuth.JWKSURL,
+				Audience: cfg.OAuth.Audience,
+				Scopes:   cfg.OAuth.Scopes,
+			}
+			handler, err = oauth.protect(context.Background(), handler)
+			if err != nil {
+				slog.Error("Failed to set up OAuth", "error", err)
+				os.Exit(1)
+			}
+		} else {
+			slog.Warn("The HTTP transport is not protected, anyone who can reach it can query SUSE Observability. Use -oauth-resource to require OAuth access tokens")
+		}
+
+		// Run the server on the HTTP transport.
+		slog.Info("Server listening", "address", cfg.HTTP, "token_passthrough", cfg.TokenPassthrough, "oauth", useOAuth)
+		if err := http.ListenAndServe(cfg.HTTP, handler); err != nil {
+			slog.Error("Server failed", "error", err)
+		}
+	}
+}
+
+var implementation = &mcp.Implementation{Name: "SUSE Observability MCP server", Version: "v0.0.1"}
+
+// newServer creates an MCP server whose tools query the SUSE Observability instances, the first one by default
+func newServer(instances []tools.Instance, cfg *config.Config, opts *mcp.ServerOptions) *mcp.Server {
+	if opts == nil {
+		opts = &mcp.ServerOptions{}
+	}
+	opts.Instructions = `Every time argument (start, end, at, from, to, time) accepts the same expressions:
+		'now', 'today', 'yesterday', an anchor with an offset ('now-2h', 'today+9h'), a duration meaning "ago" ('1h', '7d', '1w'),
+		an RFC3339 timestamp ('2025-01-02T03:12:00Z'), a date ('2025-01-02') or a Unix timestamp in seconds or milliseconds.`
+
+	r := &toolRegistry{enabled: cfg.ToolEnabled}
+	if len(instances) > 1 {
+		for _, i := range instances {
+			r.instances = append(r.instances, i.Name)
+		}
+		opts.Instructions += fmt.Sprintf(`
+		Several SUSE Observability instances are configured: %s. Every tool takes an instance argument naming the instance to query,
+		and queries %s when it is not given. Component, monitor, event and trace IDs are only valid on the instance they come from.
+		Use queryInstances to run getComponents or getMetrics on every instance at once.`, strings.Join(r.instances, ", "), r.instances[0])
+	} else {
+		// Merging the results of a single instance is of no use
+		r.enabled = func(name string) bool { return name != "queryInstances" && cfg.ToolEnabled(name) }
+	}
+	r.server = mcp.NewServer(implementation, opts)
+	registerTools(r, instances, toolOptions(cfg))
+	return r.server
+}
+
+func toolOptions(cfg *config.Config) tools.Options {
+	return tools.Options{QueryTimeout: cfg.QueryTimeout(), DefaultWindows: cfg.DefaultWindows}
+}
+
+// validateTools checks the tool names and the tool options of the configuration
+func validateTools(cfg *config.Config) error {
+	r := &toolRegistry{server: mcp.NewServer(implementation, nil), enabled: func(string) bool { return false }}
+	registerTools(r, nil, tools.Options{})
+
+	var errs []error
+	for _, name := range append(slices.Clone(cfg.Tools), cfg.DisabledTools...) {
+		if !slices.Contains(r.names, name) {
+			errs = append(errs, fmt.Errorf("unknown tool %q", name))
+		}
 	}
+	if err := toolOptions(cfg).Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
 
-	mcpTools := tools.NewBaseTool(client)
+// toolRegistry adds the tools the configuration enables to a server, and records the name of every tool
+type toolRegistry struct {
+	server  *mcp.Server
+	enabled func(name string) bool
+	names   []string
+	// instances are the names of the instances, the default one first, when there are several
+	instances []string
+}
+
+func addTool[In, Out any](r *toolRegistry, t *mcp.Tool, h mcp.ToolHandlerFor[In, Out]) {
+	r.names = append(r.names, t.Name)
+	if !r.enabled(t.Name) {
+		return
+	}
+	if len(r.instances) > 0 {
+		// List the instances in the schema, so clients only pick configured ones
+		schema, err := jsonschema.For[In](&jsonschema.ForOptions{})
+		if err != nil {
+			panic(fmt.Sprintf("input schema of %s: %v", t.Name, err))
+		}
+		if instance, ok := schema.Properties["instance"]; ok {
+			instance.Description = fmt.Sprintf("Name of the SUSE Observability instance to query: %s. Defaults to %s.", strings.Join(r.instances, ", "), r.instances[0])
+			for _, name := range r.instances {
+				instance.Enum = append(instance.Enum, name)
+			}
+		}
+		if instances, ok := schema.Properties["instances"]; ok {
+			instances.Description += fmt.Sprintf(" The instances are: %s.", strings.Join(r.instances, ", "))
+		}
+		t.InputSchema = schema
+	}
+	mcp.AddTool(r.server, t, h)
+}
 
-	mcpServer := mcp.NewServer(&mcp.Implementation{Name: "SUSE Observability MCP server", Version: "v0.0.1"}, nil)
+// registerTools registers every tool, each querying the SUSE Observability instances
+func registerTools(r *toolRegistry, instances []tools.Instance, opts tools.Options) {
+	mcpTools := tools.NewBaseTool(instances, opts)
 
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	addTool(r, &mcp.Tool{
 		Name: "getComponents",
 		Description: `Searches for topology components using STQL filters.
 		Arguments (all support comma-separated values for multiple items):
@@ -43,34 +194,160 @@ func main() {
 		- with_neighbors (optional): Include connected components using withNeighborsOf.
 		- with_neighbors_levels (optional): Number of levels (1-14) or 'all' (default: 1).
 		- with_neighbors_direction (optional): 'up', 'down', or 'both' (default: both).
+		- at (optional): Query the topology as it was at this time, as an RFC3339 timestamp (e.g., '2025-01-02T03:12:00Z') or a duration ago (e.g., '2h'). Default: now.
+		- output (optional): 'table', 'graph' (adds an adjacency list of relations with their types), 'mermaid' or 'dot' (graph plus a diagram). Default: 'table'.
 		At least one filter must be provided. All filters use STQL IN operator for efficient multi-value queries.
 		Returns:
-		A markdown table of matching components with their IDs and identifiers`},
+		A markdown table of matching components with their IDs, type, layer, domain (cluster) and health state, and the relations between them in graph modes`},
 		mcpTools.GetComponents,
 	)
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	addTool(r, &mcp.Tool{
+		Name: "compareTopology",
+		Description: `Compares the topology matched by the same filters at two points in time.
+		Arguments:
+		- names, types, healthstates, domains, namespace, with_neighbors, with_neighbors_levels, with_neighbors_direction (optional): Same component filters as getComponents.
+		- query (optional): Raw STQL topology query, used instead of the component filters.
+		- from (optional): Earlier point in time, as an RFC3339 timestamp or a duration ago (e.g., '1h', default: '1h').
+		- to (optional): Later point in time, as 'now', an RFC3339 timestamp or a duration ago (default: 'now').
+		At least one component filter or a raw STQL query must be provided.
+		Returns:
+		Markdown tables of added and removed components, health state changes, added or removed relations and relations whose endpoints or type changed.`},
+		mcpTools.CompareTopology,
+	)
+	addTool(r, &mcp.Tool{
+		Name: "investigateComponent",
+		Description: `Investigates a component in one call: walks its dependencies and dependents, collects their health states, failing monitors and recent change events, and ranks the likely root causes.
+		Use it first when a component is unhealthy, instead of querying every neighbor by hand.
+		Arguments:
+		- component_id (required): The ID of the component to investigate (from getComponents results).
+		- levels (optional): Number of dependency levels (1-14) or 'all' to walk (default: '3').
+		- direction (optional): 'down' (dependencies), 'up' (dependents) or 'both' (default: 'both').
+		- start (optional): Start of the window to look for changes in (e.g., '2h', default: '2h').
+		- end (optional): End of the window to look for changes in (default: 'now').
+		- limit (optional): Maximum number of likely causes to return (default: 10).
+		Returns:
+		A ranked markdown table of the likely root causes with their relation to the component, health state, failing monitors, recent changes and the reasons of their score.`},
+		mcpTools.InvestigateComponent,
+	)
+	addTool(r, &mcp.Tool{
+		Name: "describeSchema",
+		Description: `Lists the valid component types, layers, domains (clusters) and relation types of the SUSE Observability instance.
+		Use it to find the exact values to pass to the getComponents filters instead of guessing them.
+		Arguments:
+		- kinds (optional): What to list (comma-separated): 'component_types', 'layers', 'domains', 'relation_types'. Default: all.
+		- search (optional): Only list entries whose name, identifier or description contains this text (case-insensitive).
+		Returns:
+		Markdown tables with the names, IDs, identifiers, descriptions and owners of each kind.`},
+		mcpTools.DescribeSchema,
+	)
+	addTool(r, &mcp.Tool{
 		Name: "listMetrics",
-		Description: `Lists metrics for a specific component.
+		Description: `Lists metrics for a specific component, optionally with a summary of their data.
+		Use with_data to see how a component is doing in one call.
 		Arguments:
 		- component_id (required): The ID of the component to list bound metrics for.
+		- with_data (optional): Evaluate every bound query over the window (default: false).
+		- start (optional): Start time of the window (e.g., '1h', 'today', default: '1h').
+		- end (optional): End time of the window (e.g., 'now', default: 'now').
 		Returns:
-		A markdown table showing the bound metrics with their names, units, and query expressions.`,
+		A markdown table showing the bound metrics with their names, units, and query expressions.
+		With with_data, the current value, average, peak and a sparkline of every series instead.`,
 	},
 		mcpTools.ListMetrics,
 	)
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	addTool(r, &mcp.Tool{
 		Name: "getMetrics",
 		Description: `Query metrics from SUSE Observability over a range of time.
 		Arguments:
 		- query (required): The PromQL query to execute.
-		- start (required): Start time for the query (e.g., 'now', '1h', '24h').
-		- end (required): End time for the query (e.g., 'now', '1h').
-		- step (optional): Query resolution step width (e.g., '15s', '1m', '5m'). Default: '1m'.
+		- start (required): Start time for the query (e.g., '1h', '7d', 'now-2h', 'yesterday', '2025-01-02T03:12:00Z' or a Unix timestamp).
+		- end (required): End time for the query (e.g., 'now', '1h', 'today+9h', '2025-01-02T04:00:00Z').
+		- step (optional): Query resolution step width (e.g., '15s', '1m', '5m'). Default: chosen from the time range, fine enough for about 1500 points in summary output and to fit max_points in raw output.
+		- output (optional): 'summary' (statistics per series) or 'raw' (every point of every series). Default: 'summary'.
+		- max_points (optional): Maximum number of points per series, at least 3 (default: 120). In raw mode, series with more points are downsampled with LTTB, keeping their shape.
 		Returns:
-		A markdown table showing the time series data with timestamps, values, and labels.`},
+		A markdown table with one row per series with its min, max, mean, p50/p95/p99, first and last values, trend and the times of the extremes, with labels as columns.
+		In raw mode, a markdown table showing the time series data with timestamps, values, and labels.
+		The output states the step used and whether series were downsampled.`},
 		mcpTools.QueryMetric,
 	)
-	mcp.AddTool(mcpServer, &mcp.Tool{
+	addTool(r, &mcp.Tool{
+		Name: "getMetricValue",
+		Description: `Evaluates a PromQL query at a single point in time (instant query).
+		Prefer it over getMetrics for questions about current values, e.g. the current memory of every pod in a namespace.
+		Arguments:
+		- query (required): The PromQL query to execute.
+		- time (optional): Evaluation time (e.g., 'now', '1h' ago or an RFC3339 timestamp). Default: 'now'.
+		- limit (optional): Only return the series with the highest values, up to this number. Default: all series.
+		Returns:
+		A markdown table with one row per series with its value and labels, or a single value for scalar results.`},
+		mcpTools.GetMetricValue,
+	)
+	addTool(r, &mcp.Tool{
+		Name: "searchMetricNames",
+		Description: `Searches the names of the metrics available in SUSE Observability.
+		Use it to find the exact metric names before querying them with getMetrics or getMetricValue.
+		Arguments:
+		- search (optional): Text contained in the metric names (case-insensitive), or a regular expression when regex is set.
+		- regex (optional): Interpret search as a regular expression.
+		- start (optional): Start of the window the metrics must have data in (e.g., '1h', default: '1h').
+		- end (optional): End of the window (e.g., 'now', default: 'now').
+		- limit (optional): Maximum number of metric names to return (default: 100).
+		Returns:
+		A sorted list of matching metric names.`},
+		mcpTools.SearchMetricNames,
+	)
+	addTool(r, &mcp.Tool{
+		Name: "getMetricLabels",
+		Description: `Lists the label names and values of a metric over a time window.
+		Use it to find the label values to filter a PromQL query on.
+		Arguments:
+		- metric (required): The metric name or a series selector (e.g., 'container_memory_usage' or 'up{namespace="prod"}').
+		- label (optional): Only list the values of this label.
+		- start (optional): Start time (e.g., '1h', default: '1h').
+		- end (optional): End time (e.g., 'now', default: 'now').
+		- max_values (optional): Maximum number of values to show per label (default: 20).
+		Returns:
+		A markdown table with each label, its number of distinct values and the values.`},
+		mcpTools.GetMetricLabels,
+	)
+	addTool(r, &mcp.Tool{
+		Name: "detectAnomalies",
+		Description: `Detects anomalies in the series of a PromQL range query and groups the anomalous points in intervals.
+		Use it to tell a real spike or drop from normal variance.
+		Arguments:
+		- query (required): The PromQL query to analyze.
+		- start (optional): Start time (e.g., '6h', 'today', default: '6h').
+		- end (optional): End time (e.g., 'now', default: 'now').
+		- step (optional): Query resolution step. Chosen from the time range when empty.
+		- method (optional): 'mad' (robust distance to the median, default), 'zscore' (distance to the mean) or 'seasonal' (compared to the same window one season ago).
+		- season (optional): Season of the seasonal method, '1d' or '1w' (default: '1d').
+		- threshold (optional): Score from which a point is anomalous (default: 3 for zscore, 3.5 for mad and seasonal).
+		- limit (optional): Maximum number of intervals to return, most severe first (default: 50).
+		Returns:
+		A markdown table with the anomalous intervals of every series: start, end, direction, peak and expected values, score and severity.`},
+		mcpTools.DetectAnomalies,
+	)
+	addTool(r, &mcp.Tool{
+		Name: "correlateMetrics",
+		Description: `Ranks candidate metrics by their lagged cross-correlation with a target metric, and tells whether each candidate moves before or after the target.
+		Use it to find which neighbor metric explains a spike or drop in the target.
+		Arguments:
+		- target (required): The PromQL query of the metric to explain. Several series are averaged into one.
+		- candidates (optional): PromQL queries of the candidate metrics.
+		- component_id (optional): Also use the bound metrics of the neighbors of this component as candidates.
+		- levels (optional): Number of neighbor levels whose bound metrics are used (default: 1).
+		- start (optional): Start time (e.g., '2h', 'today', default: '2h').
+		- end (optional): End time (e.g., 'now', default: 'now').
+		- step (optional): Query resolution step, at least 1s. Chosen from the time range when empty.
+		- max_lag (optional): Largest lead or lag to look for (default: '15m').
+		- transform (optional): 'diff' correlates the changes between points (default), 'none' correlates the values.
+		- limit (optional): Maximum number of series to return, strongest correlation first (default: 10).
+		Returns:
+		A markdown table of the candidate series most correlated with the target, with their correlation and lead or lag.`},
+		mcpTools.CorrelateMetrics,
+	)
+	addTool(r, &mcp.Tool{
 		Name: "listMonitors",
 		Description: `Lists monitors for a specific component.
 		Arguments:
@@ -79,22 +356,118 @@ func main() {
 		A markdown table showing monitors associated with the specified component and their current states.`},
 		mcpTools.ListMonitors,
 	)
-
-	if *listenAddr == "" {
-		// Run the server on the stdio transport.
-		if err := mcpServer.Run(context.Background(), &mcp.StdioTransport{}); err != nil {
-			slog.Error("Server failed", "error", err)
-		}
-	} else {
-		// Create a streamable HTTP handler.
-		handler := mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server {
-			return mcpServer
-		}, nil)
-
-		// Run the server on the HTTP transport.
-		slog.Info("Server listening", "address", *listenAddr)
-		if err := http.ListenAndServe(*listenAddr, handler); err != nil {
-			slog.Error("Server failed", "error", err)
-		}
-	}
+	addTool(r, &mcp.Tool{
+		Name: "getMonitorsOverview",
+		Description: `Lists every monitor of the SUSE Observability instance with its runtime data.
+		Arguments (all support comma-separated values for multiple items):
+		- tags (optional): Monitor tags to match (a monitor matches when it has any of the tags).
+		- status (optional): Monitor statuses: 'ENABLED', 'DISABLED'.
+		- runtime_status (optional): Monitor runtime statuses: 'ENABLED', 'DISABLED', 'ERROR', 'WARNING'.
+		Returns:
+		A markdown table of monitors with their function, status, runtime status, health state counts and last runs, broken and noisy monitors first, followed by their recent errors.`},
+		mcpTools.GetMonitorsOverview,
+	)
+	addTool(r, &mcp.Tool{
+		Name: "getMonitorCheckStates",
+		Description: `Lists the components a monitor currently flags with the given health states.
+		Arguments:
+		- monitor (required): The ID or URN of the monitor (from getMonitorsOverview).
+		- healthstates (optional): Health states to list (comma-separated, default: 'CRITICAL,DEVIATING').
+		- limit (optional): Maximum number of check states to return per health state (default: 100).
+		Returns:
+		A markdown table of the flagged components with their health, check state IDs and messages.`},
+		mcpTools.GetMonitorCheckStates,
+	)
+	addTool(r, &mcp.Tool{
+		Name: "getMonitorCheckStatus",
+		Description: `Retrieves the details of a single monitor check state.
+		Arguments:
+		- check_state_id (required): The check state ID (from getMonitorCheckStates results).
+		- component_id (required): The element ID of the component the check state is on (from getMonitorCheckStates results).
+		Returns:
+		The check message, reason, troubleshooting steps and the metric queries behind the check.`},
+		mcpTools.GetMonitorCheckStatus,
+	)
+	addTool(r, &mcp.Tool{
+		Name: "getEvents",
+		Description: `Lists topology events (changes, deployments, alerts...) for a set of components over a time window.
+		Arguments:
+		- names, types, healthstates, domains, namespace, with_neighbors, with_neighbors_levels, with_neighbors_direction, at (optional): Same component filters as getComponents.
+		- query (optional): Raw STQL topology query, used instead of the component filters.
+		- start (optional): Start time (e.g., '1h', '24h', default: '1h').
+		- end (optional): End time (e.g., 'now', default: 'now').
+		- categories (optional): Event categories (comma-separated): 'Changes', 'Deployments', 'Alerts', 'Anomalies', 'Activities', 'Others'.
+		- event_types (optional): Event types (comma-separated).
+		- tags (optional): Event tags (comma-separated).
+		- sources (optional): Event sources (comma-separated).
+		- include_connected (optional): Include events of connected components.
+		- limit (optional): Maximum number of events to return (1-500, default: 50).
+		At least one component filter or a raw STQL query must be provided.
+		Returns:
+		A markdown timeline of events with their category, type, source, event IDs and source links.`},
+		mcpTools.GetEvents,
+	)
+	addTool(r, &mcp.Tool{
+		Name: "getEvent",
+		Description: `Retrieves the details of a single topology event.
+		Arguments:
+		- event_id (required): The identifier of the event (from getEvents results).
+		- start (optional): Start of the window the event happened in (e.g., '24h', default: '24h').
+		- end (optional): End of the window the event happened in (e.g., 'now', default: 'now').
+		Returns:
+		The event data, tags, source links and the affected components and relations.`},
+		mcpTools.GetEvent,
+	)
+	addTool(r, &mcp.Tool{
+		Name: "searchTraces",
+		Description: `Searches for spans in distributed traces.
+		Arguments (all support comma-separated values for multiple items):
+		- service_names (optional): Service names to match (e.g., 'checkout,payment').
+		- span_names (optional): Span names to match (e.g., 'GET /cart').
+		- span_kinds (optional): Span kinds: 'server', 'client', 'producer', 'consumer', 'internal', 'unspecified'.
+		- status_codes (optional): Span status codes: 'ok', 'error', 'unset'.
+		- min_duration (optional): Minimum span duration (e.g., '500ms').
+		- max_duration (optional): Maximum span duration (e.g., '10s').
+		- attributes (optional): Span attributes as key=value pairs (e.g., 'http.status_code=500').
+		- start (optional): Start time (e.g., '1h', default: '1h').
+		- end (optional): End time (e.g., 'now', default: 'now').
+		- sort_by (optional): 'start_time' (newest first) or 'duration' (slowest first). Default: 'start_time'.
+		- page (optional): Page number, starting at 0.
+		- page_size (optional): Number of spans per page (1-100, default: 20).
+		Returns:
+		A markdown table of matching spans with their trace IDs, services, status and durations.`},
+		mcpTools.SearchTraces,
+	)
+	addTool(r, &mcp.Tool{
+		Name: "getTrace",
+		Description: `Retrieves a distributed trace with all its spans.
+		Arguments:
+		- trace_id (required): The ID of the trace (from searchTraces results).
+		Returns:
+		The trace rendered as an indented span tree with services, durations, start offsets and error markers.`},
+		mcpTools.GetTrace,
+	)
+	addTool(r, &mcp.Tool{
+		Name: "getSpan",
+		Description: `Retrieves the details of a single span.
+		Arguments:
+		- trace_id (required): The ID of the trace the span belongs to.
+		- span_id (required): The ID of the span.
+		Returns:
+		The span details including its attributes, resource attributes and events.`},
+		mcpTools.GetSpan,
+	)
+	addTool(r, &mcp.Tool{
+		Name: "queryInstances",
+		Description: `Runs getComponents or getMetrics on several SUSE Observability instances at once and merges the results.
+		Use it to compare instances, or when it is not known which instance has the data.
+		Arguments:
+		- tool (required): 'getComponents' or 'getMetrics'.
+		- arguments (required): The arguments of the tool, the same as for a single instance (e.g., {"names": "checkout-service"} or {"query": "up", "start": "1h", "end": "now"}).
+		- instances (optional): Instances to query (comma-separated). Default: every instance.
+		Returns:
+		For getComponents, a single markdown table of the components of every instance with an instance column ('table' output only).
+		For getMetrics, the output of getMetrics for every instance. Instances that fail are reported next to the results of the others.`},
+		mcpTools.QueryInstances,
+	)
 }
@@ -0,0 +1,323 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/modelcontextprotocol/go-sdk/auth"
+	"github.com/modelcontextprotocol/go-sdk/oauthex"
+)
+
+const (
+	protectedResourcePath = "/.well-known/oauth-protected-resource"
+	// Keys are fetched again after jwksMaxAge, or on an unknown key ID but at most once per jwksMinRefresh
+	jwksMaxAge     = time.Hour
+	jwksMinRefresh = time.Minute
+	jwtLeeway      = time.Minute
+)
+
+var jwtAlgorithms = []jose.SignatureAlgorithm{
+	jose.RS256, jose.RS384, jose.RS512,
+	jose.PS256, jose.PS384, jose.PS512,
+	jose.ES256, jose.ES384, jose.ES512,
+	jose.EdDSA,
+}
+
+// oauthConfig protects the HTTP endpoint as an OAuth 2.1 resource server, following the MCP authorization spec
+type oauthConfig struct {
+	// Resource is the canonical URL of this MCP server, published in the protected resource metadata
+	Resource string
+	// Issuer is the authorization server. Its metadata gives the JWKS URL when JWKSURL is empty. The issuer
+	// of the tokens is only checked when it is set.
+	Issuer string
+	// JWKSURL is the key set the tokens are signed with
+	JWKSURL string
+	// Audience is the audience the tokens must be issued for, the resource when empty
+	Audience string
+	// Scopes are the scopes every token must have
+	Scopes []string
+}
+
+// protect wraps the MCP handler with bearer token verification and serves the protected resource metadata next to it
+func (cfg oauthConfig) protect(ctx context.Context, mcpHandler http.Handler) (http.Handler, error) {
+	resource, err := url.Parse(cfg.Resource)
+	if err != nil || resource.Scheme == "" || resource.Host == "" {
+		return nil, fmt.Errorf("invalid OAuth resource URL %q", cfg.Resource)
+	}
+
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+	jwksURL := cfg.JWKSURL
+	if jwksURL == "" {
+		jwksURL, err = discoverJWKS(ctx, cfg.Issuer, httpClient)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.Issuer == "" {
+		slog.Warn("No OAuth issuer is configured, so the issuer of access tokens is not checked and any token signed with a key of the JWKS for the audience is accepted. Set -oauth-issuer to check it")
+	}
+
+	audience := cfg.Audience
+	if audience == "" {
+		audience = cfg.Resource
+	}
+	verifier := &jwtVerifier{
+		issuer:   cfg.Issuer,
+		audience: audience,
+		keys:     &jwksCache{url: jwksURL, client: httpClient},
+	}
+
+	// RFC 9728 inserts the well-known path before the path of the resource
+	metadataPath := protectedResourcePath + strings.TrimSuffix(resource.Path, "/")
+	metadataURL := (&url.URL{Scheme: resource.Scheme, Host: resource.Host, Path: metadataPath}).String()
+	metadata := oauthex.ProtectedResourceMetadata{
+		Resource:               cfg.Resource,
+		ScopesSupported:        cfg.Scopes,
+		BearerMethodsSupported: []string{"header"},
+		ResourceName:           "SUSE Observability MCP server",
+	}
+	if cfg.Issuer != "" {
+		metadata.AuthorizationServers = []string{cfg.Issuer}
+	}
+
+	mux := http.NewServeMux()
+	metadataHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		_ = json.NewEncoder(w).Encode(metadata)
+	})
+	mux.Handle("GET "+metadataPath, metadataHandler)
+	if metadataPath != protectedResourcePath {
+		mux.Handle("GET "+protectedResourcePath, metadataHandler)
+	}
+	mux.Handle("/", auth.RequireBearerToken(verifier.verify, &auth.RequireBearerTokenOptions{
+		ResourceMetadataURL: metadataURL,
+		Scopes:              cfg.Scopes,
+	})(mcpHandler))
+	return mux, nil
+}
+
+// discoverJWKS reads the JWKS URL from the metadata of the authorization server (RFC 8414), trying the
+// OpenID Connect discovery path as well
+func discoverJWKS(ctx context.Context, issuer string, client *http.Client) (string, error) {
+	u, err := url.Parse(issuer)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return "", fmt.Errorf("invalid OAuth issuer %q", issuer)
+	}
+
+	var errs []error
+	for _, wellKnown := range []string{"/.well-known/oauth-authorization-server", "/.well-known/openid-configuration"} {
+		// RFC 8414 inserts the well-known path before the path of the issuer, OpenID Connect appends it
+		candidates := []string{(&url.URL{Scheme: u.Scheme, Host: u.Host, Path: wellKnown + strings.TrimSuffix(u.Path, "/")}).String()}
+		if u.Path != "" && u.Path != "/" {
+			candidates = append(candidates, strings.TrimSuffix(issuer, "/")+wellKnown)
+		}
+		for _, metadataURL := range candidates {
+			var meta struct {
+				Issuer  string `json:"issuer"`
+				JWKSURI string `json:"jwks_uri"`
+			}
+			if err := getJSON(ctx, client, metadataURL, &meta); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if meta.Issuer != issuer {
+				return "", fmt.Errorf("metadata issuer %q does not match issuer %q", meta.Issuer, issuer)
+			}
+			if meta.JWKSURI == "" {
+				return "", fmt.Errorf("the metadata of issuer %q has no jwks_uri", issuer)
+			}
+			return meta.JWKSURI, nil
+		}
+	}
+	return "", fmt.Errorf("failed to discover the authorization server metadata of %q: %w", issuer, errors.Join(errs...))
+}
+
+func getJSON(ctx context.Context, client *http.Client, url string, dest any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", url, res.Status)
+	}
+	if err := json.NewDecoder(io.LimitReader(res.Body, 1<<20)).Decode(dest); err != nil {
+		return fmt.Errorf("GET %s: %w", url, err)
+	}
+	return nil
+}
+
+// jwtVerifier validates signed JWT access tokens
+type jwtVerifier struct {
+	issuer   string
+	audience string
+	keys     *jwksCache
+}
+
+// accessTokenClaims are the claims read besides the registered ones. The scopes are either a space-separated
+// "scope" string (RFC 9068) or a "scp" list, as some providers issue.
+type accessTokenClaims struct {
+	Scope    string `json:"scope"`
+	Scp      any    `json:"scp"`
+	ClientID string `json:"client_id"`
+	Azp      string `json:"azp"`
+}
+
+func (v *jwtVerifier) verify(ctx context.Context, token string, _ *http.Request) (*auth.TokenInfo, error) {
+	parsed, err := jwt.ParseSigned(token, jwtAlgorithms)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", auth.ErrInvalidToken, err)
+	}
+	if len(parsed.Headers) != 1 {
+		return nil, fmt.Errorf("%w: expected one signature", auth.ErrInvalidToken)
+	}
+	key, err := v.keys.key(ctx, parsed.Headers[0].KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	var claims jwt.Claims
+	var extra accessTokenClaims
+	if err := parsed.Claims(key, &claims, &extra); err != nil {
+		return nil, fmt.Errorf("%w: %v", auth.ErrInvalidToken, err)
+	}
+	if err := claims.ValidateWithLeeway(jwt.Expected{
+		Issuer:      v.issuer,
+		AnyAudience: jwt.Audience{v.audience},
+		Time:        time.Now(),
+	}, jwtLeeway); err != nil {
+		return nil, fmt.Errorf("%w: %v", auth.ErrInvalidToken, err)
+	}
+
+	info := &auth.TokenInfo{
+		Scopes: strings.Fields(extra.Scope),
+		Extra:  map[string]any{"sub": claims.Subject},
+	}
+	switch scp := extra.Scp.(type) {
+	case string:
+		info.Scopes = append(info.Scopes, strings.Fields(scp)...)
+	case []any:
+		for _, s := range scp {
+			if s, ok := s.(string); ok {
+				info.Scopes = append(info.Scopes, s)
+			}
+		}
+	}
+	if claims.Expiry != nil {
+		info.Expiration = claims.Expiry.Time()
+	}
+	if clientID := cmp.Or(extra.ClientID, extra.Azp); clientID != "" {
+		info.Extra["client_id"] = clientID
+	}
+	return info, nil
+}
+
+// jwksCache keeps the key set of the authorization server, fetching it again when it gets old or a token
+// is signed with an unknown key, as happens when the keys are rotated. Keys are fetched without holding the
+// lock, so the requests with known keys are not held up by a fetch, and one fetch runs at a time.
+type jwksCache struct {
+	url    string
+	client *http.Client
+
+	mu      sync.Mutex
+	keys    jose.JSONWebKeySet
+	fetched time.Time
+	// fetching is closed when the running fetch ends, and nil when no fetch runs
+	fetching chan struct{}
+	fetchErr error
+}
+
+func (c *jwksCache) key(ctx context.Context, kid string) (*jose.JSONWebKey, error) {
+	c.mu.Lock()
+	if key := c.find(kid); key != nil && time.Since(c.fetched) < jwksMaxAge {
+		c.mu.Unlock()
+		return key, nil
+	}
+	done := c.fetching
+	fetch := done == nil && time.Since(c.fetched) >= jwksMinRefresh
+	if fetch {
+		done = make(chan struct{})
+		c.fetching = done
+		c.fetched = time.Now()
+	}
+	c.mu.Unlock()
+
+	if fetch {
+		// The fetch serves the waiting requests as well, so it does not end with the request that started it
+		c.fetch(context.WithoutCancel(ctx), done)
+	} else if done != nil {
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// Keep serving the known keys when the authorization server is unreachable
+	if key := c.find(kid); key != nil {
+		return key, nil
+	}
+	if done != nil && c.fetchErr != nil {
+		return nil, c.fetchErr
+	}
+	return nil, fmt.Errorf("%w: unknown signing key %q", auth.ErrInvalidToken, kid)
+}
+
+// find returns the signing key with the ID, or the only signing key when the token has no key ID
+func (c *jwksCache) find(kid string) *jose.JSONWebKey {
+	var keys []jose.JSONWebKey
+	if kid == "" {
+		keys = c.keys.Keys
+	} else {
+		keys = c.keys.Key(kid)
+	}
+	var found []jose.JSONWebKey
+	for _, k := range keys {
+		if k.Use == "" || k.Use == "sig" {
+			found = append(found, k)
+		}
+	}
+	if len(found) != 1 {
+		return nil
+	}
+	return &found[0]
+}
+
+// fetch reads the key set, keeping the previous keys when it fails, and closes done
+func (c *jwksCache) fetch(ctx context.Context, done chan struct{}) {
+	var keys jose.JSONWebKeySet
+	err := getJSON(ctx, c.client, c.url, &keys)
+	if err == nil && len(keys.Keys) == 0 {
+		err = errors.New("the JWKS has no keys")
+	}
+
+	c.mu.Lock()
+	if err != nil {
+		c.fetchErr = fmt.Errorf("failed to fetch JWKS: %w", err)
+	} else {
+		c.keys, c.fetchErr = keys, nil
+	}
+	c.fetching = nil
+	c.mu.Unlock()
+	close(done)
+}
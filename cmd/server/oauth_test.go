@@ -0,0 +1,323 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/modelcontextprotocol/go-sdk/auth"
+)
+
+const testResource = "https://mcp.example.com/mcp"
+
+// testIssuer is a stand-in authorization server publishing its metadata and key set
+type testIssuer struct {
+	server *httptest.Server
+	// metadataIssuer overrides the issuer published in the metadata
+	metadataIssuer string
+	jwksFetches    atomic.Int32
+
+	mu   sync.Mutex
+	keys map[string]*ecdsa.PrivateKey
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	t.Helper()
+	issuer := &testIssuer{keys: make(map[string]*ecdsa.PrivateKey)}
+	issuer.rotate(t, "key-1")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/oauth-authorization-server", func(w http.ResponseWriter, r *http.Request) {
+		iss := issuer.metadataIssuer
+		if iss == "" {
+			iss = issuer.server.URL
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"issuer": iss, "jwks_uri": issuer.server.URL + "/jwks"})
+	})
+	mux.HandleFunc("GET /jwks", func(w http.ResponseWriter, r *http.Request) {
+		issuer.jwksFetches.Add(1)
+		issuer.mu.Lock()
+		defer issuer.mu.Unlock()
+		var set jose.JSONWebKeySet
+		for kid, key := range issuer.keys {
+			set.Keys = append(set.Keys, jose.JSONWebKey{Key: key.Public(), KeyID: kid, Algorithm: string(jose.ES256), Use: "sig"})
+		}
+		_ = json.NewEncoder(w).Encode(set)
+	})
+	issuer.server = httptest.NewServer(mux)
+	t.Cleanup(issuer.server.Close)
+	return issuer
+}
+
+// rotate replaces the signing keys with a new one
+func (i *testIssuer) rotate(t *testing.T, kid string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.keys = map[string]*ecdsa.PrivateKey{kid: key}
+}
+
+func (i *testIssuer) signingKey(kid string) *ecdsa.PrivateKey {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.keys[kid]
+}
+
+// claims returns valid claims for the test resource, with the given scope
+func (i *testIssuer) claims(scope string) map[string]any {
+	now := time.Now()
+	return map[string]any{
+		"iss":   i.server.URL,
+		"aud":   testResource,
+		"sub":   "alice",
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+		"scope": scope,
+	}
+}
+
+func sign(t *testing.T, key jose.SigningKey, kid string, claims map[string]any) string {
+	t.Helper()
+	opts := (&jose.SignerOptions{}).WithType("JWT")
+	if kid != "" {
+		opts = opts.WithHeader("kid", kid)
+	}
+	signer, err := jose.NewSigner(key, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := jwt.Signed(signer).Claims(claims).Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func (i *testIssuer) sign(t *testing.T, kid string, claims map[string]any) string {
+	t.Helper()
+	return sign(t, jose.SigningKey{Algorithm: jose.ES256, Key: i.signingKey(kid)}, kid, claims)
+}
+
+// unsigned returns a token with the 'none' algorithm
+func unsigned(t *testing.T, claims map[string]any) string {
+	t.Helper()
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc := base64.RawURLEncoding.EncodeToString
+	return enc([]byte(`{"alg":"none","typ":"JWT"}`)) + "." + enc(payload) + "."
+}
+
+func TestOAuthProtect(t *testing.T) {
+	issuer := newTestIssuer(t)
+	cfg := oauthConfig{Resource: testResource, Issuer: issuer.server.URL, Scopes: []string{"mcp:read"}}
+	handler, err := cfg.protect(context.Background(), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		info := auth.TokenInfoFromContext(r.Context())
+		_, _ = w.Write([]byte(info.Extra["sub"].(string)))
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	with := func(change func(map[string]any)) map[string]any {
+		claims := issuer.claims("mcp:read mcp:write")
+		change(claims)
+		return claims
+	}
+	tests := []struct {
+		name       string
+		token      string
+		wantStatus int
+	}{
+		{name: "valid", token: issuer.sign(t, "key-1", issuer.claims("mcp:read mcp:write")), wantStatus: http.StatusOK},
+		{name: "scp list", token: issuer.sign(t, "key-1", with(func(c map[string]any) { delete(c, "scope"); c["scp"] = []string{"mcp:read"} })), wantStatus: http.StatusOK},
+		{name: "audience list", token: issuer.sign(t, "key-1", with(func(c map[string]any) { c["aud"] = []string{"other", testResource} })), wantStatus: http.StatusOK},
+		{name: "no token", wantStatus: http.StatusUnauthorized},
+		{name: "garbage", token: "not-a-jwt", wantStatus: http.StatusUnauthorized},
+		{name: "wrong issuer", token: issuer.sign(t, "key-1", with(func(c map[string]any) { c["iss"] = "https://evil.example.com" })), wantStatus: http.StatusUnauthorized},
+		{name: "missing issuer", token: issuer.sign(t, "key-1", with(func(c map[string]any) { delete(c, "iss") })), wantStatus: http.StatusUnauthorized},
+		{name: "wrong audience", token: issuer.sign(t, "key-1", with(func(c map[string]any) { c["aud"] = "https://other.example.com" })), wantStatus: http.StatusUnauthorized},
+		{name: "expired", token: issuer.sign(t, "key-1", with(func(c map[string]any) { c["exp"] = time.Now().Add(-2 * jwtLeeway).Unix() })), wantStatus: http.StatusUnauthorized},
+		{name: "missing exp", token: issuer.sign(t, "key-1", with(func(c map[string]any) { delete(c, "exp") })), wantStatus: http.StatusUnauthorized},
+		{name: "not yet valid", token: issuer.sign(t, "key-1", with(func(c map[string]any) { c["nbf"] = time.Now().Add(2 * jwtLeeway).Unix() })), wantStatus: http.StatusUnauthorized},
+		{name: "missing scope", token: issuer.sign(t, "key-1", issuer.claims("mcp:write")), wantStatus: http.StatusForbidden},
+		{name: "no scopes", token: issuer.sign(t, "key-1", with(func(c map[string]any) { delete(c, "scope") })), wantStatus: http.StatusForbidden},
+		{name: "unknown kid", token: sign(t, jose.SigningKey{Algorithm: jose.ES256, Key: mustKey(t)}, "key-9", issuer.claims("mcp:read")), wantStatus: http.StatusUnauthorized},
+		{name: "signed by another key with a known kid", token: sign(t, jose.SigningKey{Algorithm: jose.ES256, Key: mustKey(t)}, "key-1", issuer.claims("mcp:read")), wantStatus: http.StatusUnauthorized},
+		{name: "HS256", token: sign(t, jose.SigningKey{Algorithm: jose.HS256, Key: []byte("0123456789abcdef0123456789abcdef")}, "key-1", issuer.claims("mcp:read")), wantStatus: http.StatusUnauthorized},
+		{name: "none", token: unsigned(t, issuer.claims("mcp:read")), wantStatus: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			switch tt.wantStatus {
+			case http.StatusOK:
+				if rec.Body.String() != "alice" {
+					t.Errorf("body = %q, want the subject of the token", rec.Body)
+				}
+			case http.StatusUnauthorized, http.StatusForbidden:
+				want := "Bearer resource_metadata=https://mcp.example.com/.well-known/oauth-protected-resource/mcp"
+				if got := rec.Header().Get("WWW-Authenticate"); got != want {
+					t.Errorf("WWW-Authenticate = %q, want %q", got, want)
+				}
+			}
+		})
+	}
+}
+
+func mustKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestOAuthProtectedResourceMetadata(t *testing.T) {
+	issuer := newTestIssuer(t)
+	cfg := oauthConfig{Resource: testResource, Issuer: issuer.server.URL, Scopes: []string{"mcp:read"}}
+	handler, err := cfg.protect(context.Background(), http.NotFoundHandler())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// RFC 9728 inserts the well-known path before the path of the resource, the root path is served as well
+	for _, path := range []string{"/.well-known/oauth-protected-resource/mcp", "/.well-known/oauth-protected-resource"} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET %s: status = %d, want 200", path, rec.Code)
+		}
+		var metadata struct {
+			Resource             string   `json:"resource"`
+			AuthorizationServers []string `json:"authorization_servers"`
+			ScopesSupported      []string `json:"scopes_supported"`
+		}
+		if err := json.NewDecoder(rec.Body).Decode(&metadata); err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		if metadata.Resource != testResource {
+			t.Errorf("GET %s: resource = %q, want %q", path, metadata.Resource, testResource)
+		}
+		if len(metadata.AuthorizationServers) != 1 || metadata.AuthorizationServers[0] != issuer.server.URL {
+			t.Errorf("GET %s: authorization_servers = %v, want [%s]", path, metadata.AuthorizationServers, issuer.server.URL)
+		}
+		if len(metadata.ScopesSupported) != 1 || metadata.ScopesSupported[0] != "mcp:read" {
+			t.Errorf("GET %s: scopes_supported = %v, want [mcp:read]", path, metadata.ScopesSupported)
+		}
+	}
+}
+
+func TestDiscoverJWKS(t *testing.T) {
+	issuer := newTestIssuer(t)
+	client := issuer.server.Client()
+
+	jwksURL, err := discoverJWKS(context.Background(), issuer.server.URL, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := issuer.server.URL + "/jwks"; jwksURL != want {
+		t.Errorf("JWKS URL = %q, want %q", jwksURL, want)
+	}
+
+	// A metadata document of another issuer must not be trusted
+	issuer.metadataIssuer = "https://evil.example.com"
+	if _, err := discoverJWKS(context.Background(), issuer.server.URL, client); err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Errorf("discoverJWKS with a mismatching issuer = %v, want a mismatch error", err)
+	}
+	cfg := oauthConfig{Resource: testResource, Issuer: issuer.server.URL}
+	if _, err := cfg.protect(context.Background(), http.NotFoundHandler()); err == nil {
+		t.Error("protect with a mismatching issuer succeeded, want an error")
+	}
+}
+
+func TestJWKSRotation(t *testing.T) {
+	issuer := newTestIssuer(t)
+	keys := &jwksCache{url: issuer.server.URL + "/jwks", client: issuer.server.Client()}
+	verifier := &jwtVerifier{issuer: issuer.server.URL, audience: testResource, keys: keys}
+	verify := func(kid string) error {
+		_, err := verifier.verify(context.Background(), issuer.sign(t, kid, issuer.claims("mcp:read")), nil)
+		return err
+	}
+
+	if err := verify("key-1"); err != nil {
+		t.Fatalf("verify with the first key: %v", err)
+	}
+	if err := verify("key-1"); err != nil || issuer.jwksFetches.Load() != 1 {
+		t.Fatalf("verify with a cached key: %v after %d fetches, want 1 fetch", err, issuer.jwksFetches.Load())
+	}
+
+	issuer.rotate(t, "key-2")
+	// Unknown keys are not fetched more than once per jwksMinRefresh
+	if err := verify("key-2"); !errors.Is(err, auth.ErrInvalidToken) {
+		t.Fatalf("verify with a rotated key right after a fetch = %v, want an invalid token", err)
+	}
+	if n := issuer.jwksFetches.Load(); n != 1 {
+		t.Fatalf("JWKS fetched %d times, want 1", n)
+	}
+
+	keys.mu.Lock()
+	keys.fetched = time.Now().Add(-jwksMinRefresh)
+	keys.mu.Unlock()
+	if err := verify("key-2"); err != nil {
+		t.Fatalf("verify with a rotated key: %v", err)
+	}
+	if n := issuer.jwksFetches.Load(); n != 2 {
+		t.Fatalf("JWKS fetched %d times, want 2", n)
+	}
+}
+
+func TestJWKSConcurrentFetch(t *testing.T) {
+	issuer := newTestIssuer(t)
+	keys := &jwksCache{url: issuer.server.URL + "/jwks", client: issuer.server.Client()}
+	verifier := &jwtVerifier{issuer: issuer.server.URL, audience: testResource, keys: keys}
+	token := issuer.sign(t, "key-1", issuer.claims("mcp:read"))
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := verifier.verify(context.Background(), token, nil)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := issuer.jwksFetches.Load(); n != 1 {
+		t.Errorf("JWKS fetched %d times by concurrent requests, want 1", n)
+	}
+}
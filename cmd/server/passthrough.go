@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"suse-observability-mcp/internal/config"
+	"suse-observability-mcp/internal/tools"
+)
+
+const sessionIDHeader = "Mcp-Session-Id"
+
+// sessionIdleTimeout closes the sessions that send no request for that long
+const sessionIdleTimeout = 30 * time.Minute
+
+// tokenPassthrough serves every MCP session with its own client, authenticated with the SUSE Observability
+// token the caller sends. A session is bound to the token that created it, so another token cannot reuse it.
+type tokenPassthrough struct {
+	base     tools.Instance
+	cfg      *config.Config
+	apiToken bool
+	// bearer accepts the token in the Authorization header, which OAuth uses otherwise
+	bearer      bool
+	idleTimeout time.Duration
+
+	mu       sync.Mutex
+	sessions map[string]*sessionOwner
+}
+
+// sessionOwner is the token a session is bound to
+type sessionOwner struct {
+	hash    [sha256.Size]byte
+	created time.Time
+	// connected is set once the session receives its first message, from when the owner is forgotten as the
+	// session closes. Owners of sessions that never connect are forgotten after the idle timeout.
+	connected bool
+}
+
+func newTokenPassthrough(base tools.Instance, cfg *config.Config, bearer bool) *tokenPassthrough {
+	return &tokenPassthrough{
+		base:        base,
+		cfg:         cfg,
+		apiToken:    cfg.APIToken,
+		bearer:      bearer,
+		idleTimeout: sessionIdleTimeout,
+		sessions:    make(map[string]*sessionOwner),
+	}
+}
+
+// handler returns the streamable HTTP handler, rejecting requests without a token or with the token of another session
+func (p *tokenPassthrough) handler() http.Handler {
+	mcpHandler := mcp.NewStreamableHTTPHandler(p.server, &mcp.StreamableHTTPOptions{SessionTimeout: p.idleTimeout})
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, apiToken, ok := p.requestToken(r)
+		if !ok {
+			if p.bearer {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="SUSE Observability"`)
+			}
+			http.Error(w, "a SUSE Observability token is required in the Authorization, X-API-Token or X-API-Key header", http.StatusUnauthorized)
+			return
+		}
+
+		if id := r.Header.Get(sessionIDHeader); id != "" {
+			p.mu.Lock()
+			owner, found := p.sessions[id]
+			p.mu.Unlock()
+			if found && owner.hash != tokenHash(token, apiToken) {
+				http.Error(w, "the session belongs to another token", http.StatusForbidden)
+				return
+			}
+		}
+
+		mcpHandler.ServeHTTP(w, r)
+	})
+}
+
+// server creates the server of a new session, with a client using the token of the request
+func (p *tokenPassthrough) server(r *http.Request) *mcp.Server {
+	token, apiToken, ok := p.requestToken(r)
+	if !ok {
+		return nil
+	}
+	hash := tokenHash(token, apiToken)
+	instance := tools.Instance{Name: p.base.Name, Client: p.base.Client.WithToken(token, apiToken)}
+	server := newServer([]tools.Instance{instance}, p.cfg, &mcp.ServerOptions{
+		GetSessionID: func() string {
+			id := rand.Text()
+			p.addSession(id, hash)
+			return id
+		},
+	})
+	// Every session has its own server, so the first message it receives is the one of that session
+	var connected sync.Once
+	server.AddReceivingMiddleware(func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if session, ok := req.GetSession().(*mcp.ServerSession); ok {
+				connected.Do(func() { p.watchSession(session) })
+			}
+			return next(ctx, method, req)
+		}
+	})
+	return server
+}
+
+// addSession binds a new session to the token hash, forgetting the sessions that never connected
+func (p *tokenPassthrough) addSession(id string, hash [sha256.Size]byte) {
+	now := time.Now()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for sid, owner := range p.sessions {
+		if !owner.connected && now.Sub(owner.created) >= p.idleTimeout {
+			delete(p.sessions, sid)
+		}
+	}
+	p.sessions[id] = &sessionOwner{hash: hash, created: now}
+}
+
+// watchSession forgets the owner of the session once it is closed, by the client or after the idle timeout
+func (p *tokenPassthrough) watchSession(session *mcp.ServerSession) {
+	id := session.ID()
+	p.mu.Lock()
+	if owner, ok := p.sessions[id]; ok {
+		owner.connected = true
+	}
+	p.mu.Unlock()
+
+	go func() {
+		_ = session.Wait()
+		p.mu.Lock()
+		delete(p.sessions, id)
+		p.mu.Unlock()
+	}()
+}
+
+// requestToken reads the token from the X-API-Token or X-API-Key header, the headers SUSE Observability
+// itself accepts, or from a bearer Authorization header whose token type is set by the -apitoken flag
+// when OAuth does not use it
+func (p *tokenPassthrough) requestToken(r *http.Request) (string, bool, bool) {
+	if token := r.Header.Get("X-API-Token"); token != "" {
+		return token, true, true
+	}
+	if token := r.Header.Get("X-API-Key"); token != "" {
+		return token, false, true
+	}
+	if !p.bearer {
+		return "", false, false
+	}
+	scheme, token, found := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", false, false
+	}
+	return strings.TrimSpace(token), p.apiToken, true
+}
+
+func tokenHash(token string, apiToken bool) [sha256.Size]byte {
+	if apiToken {
+		token = "api:" + token
+	}
+	return sha256.Sum256([]byte(token))
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"suse-observability-mcp/client/suseobservability"
+	"suse-observability-mcp/internal/config"
+	"suse-observability-mcp/internal/tools"
+)
+
+const initializeRequest = `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"test","version":"1.0"}}}`
+
+func newTestPassthrough(t *testing.T) *tokenPassthrough {
+	t.Helper()
+	client, err := suseobservability.NewClient("https://observability.example.com", "", false, suseobservability.Options{})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	return newTokenPassthrough(tools.Instance{Name: "default", Client: client}, &config.Config{}, true)
+}
+
+func mcpRequest(method, session, token, body string) *http.Request {
+	r := httptest.NewRequest(method, "/mcp", strings.NewReader(body))
+	r.Header.Set("Accept", "application/json, text/event-stream")
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("X-API-Key", token)
+	if session != "" {
+		r.Header.Set(sessionIDHeader, session)
+	}
+	return r
+}
+
+func (p *tokenPassthrough) owner(id string) (sessionOwner, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	owner, ok := p.sessions[id]
+	if !ok {
+		return sessionOwner{}, false
+	}
+	return *owner, true
+}
+
+func TestTokenPassthroughSessions(t *testing.T) {
+	p := newTestPassthrough(t)
+	handler := p.handler()
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, mcpRequest(http.MethodPost, "", "token-1", initializeRequest))
+	if w.Code != http.StatusOK {
+		t.Fatalf("initialize status = %d, want %d: %s", w.Code, http.StatusOK, w.Body)
+	}
+	id := w.Header().Get(sessionIDHeader)
+	if owner, ok := p.owner(id); !ok || !owner.connected {
+		t.Fatalf("owner of session %q = %+v, %v, want a connected owner", id, owner, ok)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, mcpRequest(http.MethodDelete, id, "token-2", ""))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("delete with another token status = %d, want %d", w.Code, http.StatusForbidden)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, mcpRequest(http.MethodDelete, id, "token-1", ""))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("delete status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, ok := p.owner(id); !ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("owner of closed session %q was not forgotten", id)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestTokenPassthroughForgetsUnconnectedSessions(t *testing.T) {
+	p := newTestPassthrough(t)
+	p.idleTimeout = time.Minute
+	hash := tokenHash("token", false)
+	p.sessions["stale"] = &sessionOwner{hash: hash, created: time.Now().Add(-2 * time.Minute)}
+	p.sessions["recent"] = &sessionOwner{hash: hash, created: time.Now()}
+	p.sessions["connected"] = &sessionOwner{hash: hash, created: time.Now().Add(-2 * time.Minute), connected: true}
+
+	p.addSession("new", hash)
+
+	for id, want := range map[string]bool{"stale": false, "recent": true, "connected": true, "new": true} {
+		if _, ok := p.owner(id); ok != want {
+			t.Errorf("session %q kept = %v, want %v", id, ok, want)
+		}
+	}
+}
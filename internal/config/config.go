@@ -0,0 +1,456 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix is the prefix of the environment variables read by Load
+const EnvPrefix = "SUSE_OBSERVABILITY_"
+
+// Config is the configuration of the server. It is layered, every layer overriding the previous one:
+// defaults, the YAML configuration file, SUSE_OBSERVABILITY_* environment variables and the flags
+// given on the command line.
+type Config struct {
+	// Instance is the SUSE Observability instance named DefaultInstance, unless its URL is empty
+	Instance `yaml:",inline"`
+	// Instances are more SUSE Observability instances the tools can query, by name
+	Instances map[string]Instance `yaml:"instances"`
+	// DefaultInstance is the instance the tools query when not given one
+	DefaultInstance string `yaml:"default_instance"`
+
+	HTTP             string `yaml:"http"`
+	TokenPassthrough bool   `yaml:"token_passthrough"`
+	OAuth            OAuth  `yaml:"oauth"`
+
+	Timeouts Timeouts `yaml:"timeouts"`
+	// DefaultWindows overrides how far back the tools look when no start time is given, by tool name
+	DefaultWindows map[string]string `yaml:"default_windows"`
+	// Tools are the only tools registered when not empty
+	Tools         []string `yaml:"tools"`
+	DisabledTools []string `yaml:"disabled_tools"`
+}
+
+// Instance is the connection to a SUSE Observability instance
+type Instance struct {
+	URL       string `yaml:"url"`
+	Token     string `yaml:"token"`
+	TokenFile string `yaml:"token_file"`
+	APIToken  bool   `yaml:"api_token"`
+	TLS       TLS    `yaml:"tls"`
+}
+
+// NamedInstance is an instance with its name
+type NamedInstance struct {
+	Name string
+	Instance
+}
+
+type TLS struct {
+	CA       string `yaml:"ca"`
+	Cert     string `yaml:"cert"`
+	Key      string `yaml:"key"`
+	Insecure bool   `yaml:"insecure"`
+}
+
+type OAuth struct {
+	Resource string   `yaml:"resource"`
+	Issuer   string   `yaml:"issuer"`
+	JWKSURL  string   `yaml:"jwks_url"`
+	Audience string   `yaml:"audience"`
+	Scopes   []string `yaml:"scopes"`
+}
+
+type Timeouts struct {
+	// Request limits every request to SUSE Observability
+	Request time.Duration `yaml:"request"`
+	// Query is the timeout of PromQL queries, enforced by SUSE Observability
+	Query time.Duration `yaml:"query"`
+}
+
+// Default returns the configuration used for the settings no layer sets
+func Default() *Config {
+	return &Config{
+		Timeouts: Timeouts{
+			Request: 60 * time.Second,
+			Query:   30 * time.Second,
+		},
+	}
+}
+
+// defaultInstanceName names the instance of the top-level settings when no default instance is given
+const defaultInstanceName = "default"
+
+var instanceName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// setting is a configuration value that can be set with a flag and an environment variable
+type setting struct {
+	flag    string
+	env     string
+	usage   string
+	boolean bool
+	set     func(c *Config, v string) error
+}
+
+var settings = []setting{
+	{flag: "url", env: "URL", usage: "SUSE Observability API URL",
+		set: func(c *Config, v string) error { c.URL = v; return nil }},
+	{flag: "token", env: "TOKEN", usage: "SUSE Observability API Token. Prefer -token-file, the command line is visible to other users",
+		set: func(c *Config, v string) error { c.Token, c.TokenFile = v, ""; return nil }},
+	{flag: "token-file", env: "TOKEN_FILE", usage: "File holding the SUSE Observability token, such as a mounted secret",
+		set: func(c *Config, v string) error { c.TokenFile, c.Token = v, ""; return nil }},
+	{flag: "apitoken", env: "TOKEN_IS_API_TOKEN", usage: "Indicates if the token is an API token, instead of a service token", boolean: true,
+		set: func(c *Config, v string) (err error) { c.APIToken, err = parseBool(v); return }},
+	{flag: "tls-ca", env: "TLS_CA", usage: "PEM bundle of certificate authorities to trust in addition to the system roots",
+		set: func(c *Config, v string) error { c.TLS.CA = v; return nil }},
+	{flag: "tls-cert", env: "TLS_CERT", usage: "PEM client certificate for mutual TLS",
+		set: func(c *Config, v string) error { c.TLS.Cert = v; return nil }},
+	{flag: "tls-key", env: "TLS_KEY", usage: "PEM client key for mutual TLS",
+		set: func(c *Config, v string) error { c.TLS.Key = v; return nil }},
+	{flag: "tls-insecure", env: "TLS_INSECURE", usage: "Skip the verification of the SUSE Observability server certificate (not for production)", boolean: true,
+		set: func(c *Config, v string) (err error) { c.TLS.Insecure, err = parseBool(v); return }},
+	{flag: "default-instance", env: "DEFAULT_INSTANCE", usage: "Name of the instance the tools query when not given one, among the instances of the configuration file",
+		set: func(c *Config, v string) error { c.DefaultInstance = v; return nil }},
+	{flag: "http", env: "HTTP", usage: "address for http transport, defaults to stdio",
+		set: func(c *Config, v string) error { c.HTTP = v; return nil }},
+	{flag: "token-passthrough", env: "TOKEN_PASSTHROUGH", usage: "In HTTP mode, query SUSE Observability with the token each session sends instead of -token", boolean: true,
+		set: func(c *Config, v string) (err error) { c.TokenPassthrough, err = parseBool(v); return }},
+	{flag: "oauth-resource", env: "OAUTH_RESOURCE", usage: "Canonical URL of this MCP server (e.g. https://mcp.example.com/mcp), required to protect the HTTP transport with OAuth",
+		set: func(c *Config, v string) error { c.OAuth.Resource = v; return nil }},
+	{flag: "oauth-issuer", env: "OAUTH_ISSUER", usage: "OAuth authorization server (issuer) the access tokens come from",
+		set: func(c *Config, v string) error { c.OAuth.Issuer = v; return nil }},
+	{flag: "oauth-jwks-url", env: "OAUTH_JWKS_URL", usage: "JWKS URL to verify access tokens with, discovered from the issuer when empty",
+		set: func(c *Config, v string) error { c.OAuth.JWKSURL = v; return nil }},
+	{flag: "oauth-audience", env: "OAUTH_AUDIENCE", usage: "Audience access tokens must be issued for, defaults to -oauth-resource",
+		set: func(c *Config, v string) error { c.OAuth.Audience = v; return nil }},
+	{flag: "oauth-scopes", env: "OAUTH_SCOPES", usage: "Comma-separated scopes every access token must have",
+		set: func(c *Config, v string) error { c.OAuth.Scopes = splitList(v); return nil }},
+	{flag: "request-timeout", env: "REQUEST_TIMEOUT", usage: "Timeout of every request to SUSE Observability (default 60s)",
+		set: func(c *Config, v string) (err error) { c.Timeouts.Request, err = time.ParseDuration(v); return }},
+	{flag: "query-timeout", env: "QUERY_TIMEOUT", usage: "Timeout of PromQL queries, enforced by SUSE Observability (default 30s)",
+		set: func(c *Config, v string) (err error) { c.Timeouts.Query, err = time.ParseDuration(v); return }},
+	{flag: "default-windows", env: "DEFAULT_WINDOWS", usage: "Comma-separated default windows of the tools, e.g. 'getMetrics=6h,getEvents=24h'",
+		set: func(c *Config, v string) error {
+			windows := make(map[string]string)
+			for _, w := range splitList(v) {
+				name, window, ok := strings.Cut(w, "=")
+				if !ok {
+					return fmt.Errorf("expected tool=window, got %q", w)
+				}
+				windows[strings.TrimSpace(name)] = strings.TrimSpace(window)
+			}
+			c.DefaultWindows = windows
+			return nil
+		}},
+	{flag: "tools", env: "TOOLS", usage: "Comma-separated tools to register, all tools when empty",
+		set: func(c *Config, v string) error { c.Tools = splitList(v); return nil }},
+	{flag: "disabled-tools", env: "DISABLED_TOOLS", usage: "Comma-separated tools not to register",
+		set: func(c *Config, v string) error { c.DisabledTools = splitList(v); return nil }},
+}
+
+// Load builds the configuration from the command line arguments and the environment, reads the token
+// file and validates the result. The configuration file is given with -config or SUSE_OBSERVABILITY_CONFIG.
+func Load(args []string, getenv func(string) string) (*Config, error) {
+	fs := flag.NewFlagSet("suse-observability-mcp-server", flag.ContinueOnError)
+	configFile := fs.String("config", "", "YAML configuration file (env "+EnvPrefix+"CONFIG)")
+
+	// Flags are applied after the file and the environment, in the order they are given
+	type flagValue struct {
+		setting setting
+		value   string
+	}
+	var flagValues []flagValue
+	for _, s := range settings {
+		usage := fmt.Sprintf("%s (env %s%s)", s.usage, EnvPrefix, s.env)
+		record := func(v string) error {
+			flagValues = append(flagValues, flagValue{s, v})
+			return nil
+		}
+		if s.boolean {
+			fs.BoolFunc(s.flag, usage, record)
+		} else {
+			fs.Func(s.flag, usage, record)
+		}
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	cfg := Default()
+
+	path := *configFile
+	if path == "" {
+		path = getenv(EnvPrefix + "CONFIG")
+	}
+	if path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	var errs []error
+	if getenv(EnvPrefix+"TOKEN") != "" && getenv(EnvPrefix+"TOKEN_FILE") != "" {
+		errs = append(errs, fmt.Errorf("%sTOKEN and %sTOKEN_FILE are both set, set only one", EnvPrefix, EnvPrefix))
+	}
+	for _, s := range settings {
+		if v := getenv(EnvPrefix + s.env); v != "" {
+			if err := s.set(cfg, v); err != nil {
+				errs = append(errs, fmt.Errorf("invalid %s%s: %w", EnvPrefix, s.env, err))
+			}
+		}
+	}
+
+	var tokenFlags int
+	for _, f := range flagValues {
+		if f.setting.flag == "token" || f.setting.flag == "token-file" {
+			tokenFlags++
+		}
+		if err := f.setting.set(cfg, f.value); err != nil {
+			errs = append(errs, fmt.Errorf("invalid -%s: %w", f.setting.flag, err))
+		}
+	}
+	if tokenFlags > 1 {
+		errs = append(errs, errors.New("-token and -token-file are both given, give only one"))
+	}
+
+	tokenFileErrs := len(errs)
+	if err := cfg.Instance.readTokenFile(); err != nil {
+		errs = append(errs, err)
+	}
+	for name, instance := range cfg.Instances {
+		if err := instance.readTokenFile(); err != nil {
+			errs = append(errs, fmt.Errorf("instance %s: %w", name, err))
+		}
+		cfg.Instances[name] = instance
+	}
+
+	// Without a token from the file, Validate would report a missing token on top of the file error
+	if err := cfg.Validate(); err != nil && len(errs) == tokenFileErrs {
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return cfg, nil
+}
+
+// loadFile applies the settings of a YAML configuration file. Unknown keys are errors, so typos do not go unnoticed.
+func (c *Config) loadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to read configuration file: %w", err)
+	}
+	defer f.Close()
+
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err := dec.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("invalid configuration file %s: %w", path, err)
+	}
+	if c.Token != "" && c.TokenFile != "" {
+		return fmt.Errorf("invalid configuration file %s: token and token_file are both set, set only one", path)
+	}
+	for name, instance := range c.Instances {
+		if instance.Token != "" && instance.TokenFile != "" {
+			return fmt.Errorf("invalid configuration file %s: token and token_file of instance %s are both set, set only one", path, name)
+		}
+	}
+	return nil
+}
+
+// readTokenFile reads the token of the instance from its token file, if any
+func (i *Instance) readTokenFile() error {
+	if i.TokenFile == "" {
+		return nil
+	}
+	token, err := os.ReadFile(i.TokenFile)
+	if err != nil {
+		return fmt.Errorf("failed to read token file: %w", err)
+	}
+	if i.Token = strings.TrimSpace(string(token)); i.Token == "" {
+		return fmt.Errorf("token file %s is empty", i.TokenFile)
+	}
+	return nil
+}
+
+// Validate checks the configuration as a whole and reports every problem at once
+func (c *Config) Validate() error {
+	var errs []error
+
+	switch {
+	case c.URL == "" && len(c.Instances) == 0:
+		errs = append(errs, errors.New("the SUSE Observability URL is required (-url, "+EnvPrefix+"URL or url)"))
+	case c.URL == "":
+		// The instances of the configuration file are the only ones
+		if c.Token != "" || c.TokenFile != "" {
+			errs = append(errs, errors.New("a SUSE Observability token is set without a URL, set the tokens of the instances in the configuration file instead"))
+		}
+		if c.DefaultInstance == "" && len(c.Instances) > 1 {
+			errs = append(errs, errors.New("the default instance is required with several instances (-default-instance, "+EnvPrefix+"DEFAULT_INSTANCE or default_instance)"))
+		} else if _, ok := c.Instances[c.DefaultInstance]; c.DefaultInstance != "" && !ok {
+			errs = append(errs, fmt.Errorf("the default instance %s is not one of the instances", c.DefaultInstance))
+		}
+	default:
+		if err := checkURL(c.URL); err != nil {
+			errs = append(errs, fmt.Errorf("invalid SUSE Observability URL: %w", err))
+		}
+		if c.Token == "" && !c.TokenPassthrough {
+			errs = append(errs, errors.New("a SUSE Observability token is required (-token-file, -token, "+EnvPrefix+"TOKEN_FILE or "+EnvPrefix+"TOKEN), unless token passthrough is enabled"))
+		}
+		if _, ok := c.Instances[c.defaultInstanceName()]; ok {
+			errs = append(errs, fmt.Errorf("instance %s is configured twice, by url and in instances. Set default_instance to another name, or the default instance to one of the instances and leave url empty", c.defaultInstanceName()))
+		}
+	}
+	if (c.TLS.Cert == "") != (c.TLS.Key == "") {
+		errs = append(errs, errors.New("both a TLS client certificate and a TLS client key are needed for mutual TLS"))
+	}
+	if c.DefaultInstance != "" && !instanceName.MatchString(c.DefaultInstance) {
+		errs = append(errs, fmt.Errorf("invalid default instance name %q, use letters, digits, '.', '_' and '-'", c.DefaultInstance))
+	}
+	for _, name := range slices.Sorted(maps.Keys(c.Instances)) {
+		instance := c.Instances[name]
+		if !instanceName.MatchString(name) {
+			errs = append(errs, fmt.Errorf("invalid instance name %q, use letters, digits, '.', '_' and '-'", name))
+		}
+		if instance.URL == "" {
+			errs = append(errs, fmt.Errorf("instance %s: the SUSE Observability URL is required", name))
+		} else if err := checkURL(instance.URL); err != nil {
+			errs = append(errs, fmt.Errorf("instance %s: invalid SUSE Observability URL: %w", name, err))
+		}
+		if instance.Token == "" {
+			errs = append(errs, fmt.Errorf("instance %s: a SUSE Observability token is required (token_file or token)", name))
+		}
+		if (instance.TLS.Cert == "") != (instance.TLS.Key == "") {
+			errs = append(errs, fmt.Errorf("instance %s: both a TLS client certificate and a TLS client key are needed for mutual TLS", name))
+		}
+	}
+
+	if c.TokenPassthrough && c.HTTP == "" {
+		errs = append(errs, errors.New("token passthrough needs the HTTP transport"))
+	}
+	if c.TokenPassthrough && len(c.Instances) > 0 {
+		errs = append(errs, errors.New("token passthrough only supports a single instance, set by url"))
+	}
+	if c.OAuth.Enabled() {
+		switch {
+		case c.HTTP == "":
+			errs = append(errs, errors.New("OAuth needs the HTTP transport"))
+		case c.OAuth.Resource == "":
+			errs = append(errs, errors.New("OAuth needs the resource URL of this server (-oauth-resource)"))
+		case c.OAuth.Issuer == "" && c.OAuth.JWKSURL == "":
+			errs = append(errs, errors.New("OAuth needs an issuer (-oauth-issuer) or a JWKS URL (-oauth-jwks-url)"))
+		}
+		for name, u := range map[string]string{"resource": c.OAuth.Resource, "issuer": c.OAuth.Issuer, "JWKS URL": c.OAuth.JWKSURL} {
+			if u != "" {
+				if err := checkURL(u); err != nil {
+					errs = append(errs, fmt.Errorf("invalid OAuth %s: %w", name, err))
+				}
+			}
+		}
+	}
+
+	if c.Timeouts.Request < 0 {
+		errs = append(errs, errors.New("the request timeout cannot be negative"))
+	}
+	if c.Timeouts.Query < 0 {
+		errs = append(errs, errors.New("the query timeout cannot be negative"))
+	}
+	for _, name := range c.DisabledTools {
+		if slices.Contains(c.Tools, name) {
+			errs = append(errs, fmt.Errorf("tool %s is both enabled and disabled", name))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// AllInstances returns every configured instance, the default one first and then the others by name
+func (c *Config) AllInstances() []NamedInstance {
+	var instances []NamedInstance
+	if c.URL != "" {
+		instances = append(instances, NamedInstance{Name: c.defaultInstanceName(), Instance: c.Instance})
+	}
+	for _, name := range slices.Sorted(maps.Keys(c.Instances)) {
+		instance := NamedInstance{Name: name, Instance: c.Instances[name]}
+		if name == c.DefaultInstance || len(c.Instances) == 1 && c.URL == "" {
+			instances = slices.Insert(instances, 0, instance)
+		} else {
+			instances = append(instances, instance)
+		}
+	}
+	return instances
+}
+
+func (c *Config) defaultInstanceName() string {
+	if c.DefaultInstance == "" {
+		return defaultInstanceName
+	}
+	return c.DefaultInstance
+}
+
+// Enabled tells whether any OAuth setting is given, which protects the HTTP transport
+func (o OAuth) Enabled() bool {
+	return o.Resource != "" || o.Issuer != "" || o.JWKSURL != "" || o.Audience != "" || len(o.Scopes) > 0
+}
+
+// ToolEnabled tells whether the tool is registered
+func (c *Config) ToolEnabled(name string) bool {
+	if len(c.Tools) > 0 && !slices.Contains(c.Tools, name) {
+		return false
+	}
+	return !slices.Contains(c.DisabledTools, name)
+}
+
+// QueryTimeout returns the query timeout in the duration format of the PromQL API, or an empty string for the default
+func (c *Config) QueryTimeout() string {
+	switch {
+	case c.Timeouts.Query <= 0:
+		return ""
+	case c.Timeouts.Query%time.Second == 0:
+		return fmt.Sprintf("%ds", c.Timeouts.Query/time.Second)
+	default:
+		return fmt.Sprintf("%dms", c.Timeouts.Query/time.Millisecond)
+	}
+}
+
+func checkURL(u string) error {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return err
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" || parsed.Host == "" {
+		return fmt.Errorf("%q is not an http(s) URL", u)
+	}
+	return nil
+}
+
+func parseBool(v string) (bool, error) {
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		// The value is not echoed, a token set in the wrong variable would end up in the logs
+		return false, errors.New("not a boolean, must be true or false")
+	}
+	return b, nil
+}
+
+func splitList(v string) []string {
+	var out []string
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
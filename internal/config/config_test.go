@@ -0,0 +1,438 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name string
+		// files are written to a temporary directory, {dir} in files, env and args is replaced by its path
+		files map[string]string
+		env   map[string]string
+		args  []string
+		// check is called with the loaded configuration when no error is expected
+		check func(t *testing.T, cfg *Config)
+		// wantErrs are substrings of the expected error, notWantErrs must not be in it
+		wantErrs    []string
+		notWantErrs []string
+	}{
+		{
+			name: "defaults",
+			args: []string{"-url", "https://observability.example.com", "-token", "secret"},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Timeouts.Request != 60*time.Second || cfg.Timeouts.Query != 30*time.Second {
+					t.Errorf("timeouts = %+v, want the defaults", cfg.Timeouts)
+				}
+				if cfg.HTTP != "" || cfg.APIToken || cfg.TokenPassthrough || cfg.OAuth.Enabled() {
+					t.Errorf("config = %+v, want the defaults", cfg)
+				}
+			},
+		},
+		{
+			name: "file",
+			files: map[string]string{"config.yaml": `
+url: https://file.example.com
+token: from-file
+api_token: true
+http: :8080
+timeouts:
+  request: 2m
+default_windows:
+  getEvents: 24h
+disabled_tools: [getTraces]
+`},
+			args: []string{"-config", "{dir}/config.yaml"},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.URL != "https://file.example.com" || cfg.Token != "from-file" || !cfg.APIToken || cfg.HTTP != ":8080" {
+					t.Errorf("config = %+v, want the settings of the file", cfg)
+				}
+				if cfg.Timeouts.Request != 2*time.Minute || cfg.Timeouts.Query != 30*time.Second {
+					t.Errorf("timeouts = %+v, want the request timeout of the file and the default query timeout", cfg.Timeouts)
+				}
+				if cfg.DefaultWindows["getEvents"] != "24h" || cfg.ToolEnabled("getTraces") || !cfg.ToolEnabled("getEvents") {
+					t.Errorf("config = %+v, want the tools of the file", cfg)
+				}
+			},
+		},
+		{
+			name:  "environment overrides the file",
+			files: map[string]string{"config.yaml": "url: https://file.example.com\ntoken: from-file\nhttp: :8080\n"},
+			env: map[string]string{
+				"SUSE_OBSERVABILITY_CONFIG":        "{dir}/config.yaml",
+				"SUSE_OBSERVABILITY_URL":           "https://env.example.com",
+				"SUSE_OBSERVABILITY_QUERY_TIMEOUT": "45s",
+				"SUSE_OBSERVABILITY_TOOLS":         "getMetrics, getEvents",
+			},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.URL != "https://env.example.com" || cfg.Token != "from-file" || cfg.HTTP != ":8080" {
+					t.Errorf("config = %+v, want the URL of the environment and the rest of the file", cfg)
+				}
+				if cfg.Timeouts.Query != 45*time.Second {
+					t.Errorf("query timeout = %s, want 45s", cfg.Timeouts.Query)
+				}
+				if !slices.Equal(cfg.Tools, []string{"getMetrics", "getEvents"}) {
+					t.Errorf("tools = %v, want [getMetrics getEvents]", cfg.Tools)
+				}
+			},
+		},
+		{
+			name:  "flags override the environment",
+			files: map[string]string{"config.yaml": "url: https://file.example.com\ntoken: from-file\n", "other.yaml": "url: https://other.example.com\n"},
+			env: map[string]string{
+				"SUSE_OBSERVABILITY_CONFIG": "{dir}/config.yaml",
+				"SUSE_OBSERVABILITY_URL":    "https://env.example.com",
+				"SUSE_OBSERVABILITY_TOKEN":  "from-env",
+			},
+			args: []string{"-url", "https://flag.example.com", "-apitoken"},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.URL != "https://flag.example.com" || cfg.Token != "from-env" || !cfg.APIToken {
+					t.Errorf("config = %+v, want the URL of the flag and the token of the environment", cfg)
+				}
+			},
+		},
+		{
+			name:  "-config overrides the environment",
+			files: map[string]string{"config.yaml": "url: https://file.example.com\ntoken: from-file\n", "other.yaml": "url: https://other.example.com\ntoken: from-other\n"},
+			env:   map[string]string{"SUSE_OBSERVABILITY_CONFIG": "{dir}/config.yaml"},
+			args:  []string{"-config", "{dir}/other.yaml"},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.URL != "https://other.example.com" || cfg.Token != "from-other" {
+					t.Errorf("config = %+v, want the settings of the file given with -config", cfg)
+				}
+			},
+		},
+		{
+			name: "boolean flag with a value",
+			env:  map[string]string{"SUSE_OBSERVABILITY_TOKEN_IS_API_TOKEN": "true"},
+			args: []string{"-url", "https://flag.example.com", "-token", "secret", "-apitoken=false"},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.APIToken {
+					t.Error("api token = true, want the false of the flag")
+				}
+			},
+		},
+		{
+			name:  "token file",
+			files: map[string]string{"token": "  from-token-file\n"},
+			args:  []string{"-url", "https://flag.example.com", "-token-file", "{dir}/token"},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Token != "from-token-file" {
+					t.Errorf("token = %q, want the trimmed content of the token file", cfg.Token)
+				}
+			},
+		},
+		{
+			name:  "-token clears the token file of the file",
+			files: map[string]string{"config.yaml": "url: https://file.example.com\ntoken_file: {dir}/missing\n"},
+			args:  []string{"-config", "{dir}/config.yaml", "-token", "from-flag"},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Token != "from-flag" || cfg.TokenFile != "" {
+					t.Errorf("token = %q, token file = %q, want the token of the flag only", cfg.Token, cfg.TokenFile)
+				}
+			},
+		},
+		{
+			name:  "-token-file clears the token of the environment",
+			files: map[string]string{"token": "from-token-file"},
+			env:   map[string]string{"SUSE_OBSERVABILITY_URL": "https://env.example.com", "SUSE_OBSERVABILITY_TOKEN": "from-env"},
+			args:  []string{"-token-file", "{dir}/token"},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Token != "from-token-file" || cfg.TokenFile != "{dir}/token" {
+					t.Errorf("token = %q, token file = %q, want the token of the token file", cfg.Token, cfg.TokenFile)
+				}
+			},
+		},
+		{
+			name:     "token and token file in the environment",
+			env:      map[string]string{"SUSE_OBSERVABILITY_URL": "https://env.example.com", "SUSE_OBSERVABILITY_TOKEN": "from-env", "SUSE_OBSERVABILITY_TOKEN_FILE": "/run/secrets/token"},
+			wantErrs: []string{"SUSE_OBSERVABILITY_TOKEN and SUSE_OBSERVABILITY_TOKEN_FILE are both set"},
+		},
+		{
+			name:     "token and token file flags",
+			files:    map[string]string{"token": "from-token-file"},
+			args:     []string{"-url", "https://flag.example.com", "-token", "from-flag", "-token-file", "{dir}/token"},
+			wantErrs: []string{"-token and -token-file are both given"},
+		},
+		{
+			name:     "token and token file in the file",
+			files:    map[string]string{"config.yaml": "url: https://file.example.com\ntoken: from-file\ntoken_file: /run/secrets/token\n"},
+			args:     []string{"-config", "{dir}/config.yaml"},
+			wantErrs: []string{"token and token_file are both set"},
+		},
+		{
+			name:     "unknown key in the file",
+			files:    map[string]string{"config.yaml": "url: https://file.example.com\ntoken: from-file\ntimeout:\n  request: 2m\n"},
+			args:     []string{"-config", "{dir}/config.yaml"},
+			wantErrs: []string{"field timeout not found"},
+		},
+		{
+			name:     "missing file",
+			args:     []string{"-config", "{dir}/missing.yaml"},
+			wantErrs: []string{"failed to read configuration file"},
+		},
+		{
+			name:        "missing token file",
+			args:        []string{"-url", "https://flag.example.com", "-token-file", "{dir}/missing"},
+			wantErrs:    []string{"failed to read token file"},
+			notWantErrs: []string{"token is required"},
+		},
+		{
+			name:        "empty token file",
+			files:       map[string]string{"token": "\n"},
+			args:        []string{"-url", "https://flag.example.com", "-token-file", "{dir}/token"},
+			wantErrs:    []string{"token file {dir}/token is empty"},
+			notWantErrs: []string{"token is required"},
+		},
+		{
+			name:        "missing token file of an instance",
+			files:       map[string]string{"config.yaml": "instances:\n  prod:\n    url: https://prod.example.com\n    token_file: {dir}/missing\n"},
+			args:        []string{"-config", "{dir}/config.yaml"},
+			wantErrs:    []string{"instance prod: failed to read token file"},
+			notWantErrs: []string{"token is required"},
+		},
+		{
+			name:     "invalid values",
+			env:      map[string]string{"SUSE_OBSERVABILITY_TLS_INSECURE": "maybe", "SUSE_OBSERVABILITY_DEFAULT_WINDOWS": "getEvents"},
+			args:     []string{"-url", "https://flag.example.com", "-token", "secret", "-request-timeout", "soon"},
+			wantErrs: []string{"invalid SUSE_OBSERVABILITY_TLS_INSECURE", "invalid SUSE_OBSERVABILITY_DEFAULT_WINDOWS", "invalid -request-timeout"},
+		},
+		{
+			name:        "token in a boolean",
+			env:         map[string]string{"SUSE_OBSERVABILITY_TOKEN_IS_API_TOKEN": "leaked-secret"},
+			args:        []string{"-url", "https://flag.example.com", "-token", "secret"},
+			wantErrs:    []string{"invalid SUSE_OBSERVABILITY_TOKEN_IS_API_TOKEN: not a boolean"},
+			notWantErrs: []string{"leaked-secret"},
+		},
+		{
+			name:     "missing URL and token",
+			wantErrs: []string{"the SUSE Observability URL is required"},
+		},
+		{
+			name:     "missing token",
+			args:     []string{"-url", "https://flag.example.com"},
+			wantErrs: []string{"a SUSE Observability token is required"},
+		},
+		{
+			name: "instances",
+			files: map[string]string{
+				"config.yaml": `
+default_instance: prod
+instances:
+  prod:
+    url: https://prod.example.com
+    token_file: {dir}/prod-token
+  staging:
+    url: https://staging.example.com
+    token: staging-token
+    api_token: true
+`,
+				"prod-token": "prod-token",
+			},
+			args: []string{"-config", "{dir}/config.yaml"},
+			check: func(t *testing.T, cfg *Config) {
+				instances := cfg.AllInstances()
+				if len(instances) != 2 || instances[0].Name != "prod" || instances[1].Name != "staging" {
+					t.Fatalf("instances = %+v, want prod then staging", instances)
+				}
+				if instances[0].Token != "prod-token" || instances[1].Token != "staging-token" || !instances[1].APIToken {
+					t.Errorf("instances = %+v, want the tokens of the instances", instances)
+				}
+			},
+		},
+		{
+			name:  "url and instances",
+			files: map[string]string{"config.yaml": "url: https://main.example.com\ntoken: main-token\ninstances:\n  staging:\n    url: https://staging.example.com\n    token: staging-token\n"},
+			args:  []string{"-config", "{dir}/config.yaml"},
+			check: func(t *testing.T, cfg *Config) {
+				instances := cfg.AllInstances()
+				if len(instances) != 2 || instances[0].Name != "default" || instances[0].URL != "https://main.example.com" || instances[1].Name != "staging" {
+					t.Errorf("instances = %+v, want default then staging", instances)
+				}
+			},
+		},
+		{
+			name:     "several instances without a default",
+			files:    map[string]string{"config.yaml": "instances:\n  prod:\n    url: https://prod.example.com\n    token: a\n  staging:\n    url: https://staging.example.com\n    token: b\n"},
+			args:     []string{"-config", "{dir}/config.yaml"},
+			wantErrs: []string{"the default instance is required with several instances"},
+		},
+		{
+			name:     "unknown default instance",
+			files:    map[string]string{"config.yaml": "instances:\n  prod:\n    url: https://prod.example.com\n    token: a\n"},
+			args:     []string{"-config", "{dir}/config.yaml", "-default-instance", "staging"},
+			wantErrs: []string{"the default instance staging is not one of the instances"},
+		},
+		{
+			name:     "default instance configured twice",
+			files:    map[string]string{"config.yaml": "url: https://main.example.com\ntoken: a\ninstances:\n  default:\n    url: https://prod.example.com\n    token: b\n"},
+			args:     []string{"-config", "{dir}/config.yaml"},
+			wantErrs: []string{"instance default is configured twice"},
+		},
+		{
+			name:     "top-level token without a URL",
+			files:    map[string]string{"config.yaml": "token: a\ninstances:\n  prod:\n    url: https://prod.example.com\n    token: b\n"},
+			args:     []string{"-config", "{dir}/config.yaml"},
+			wantErrs: []string{"a SUSE Observability token is set without a URL"},
+		},
+		{
+			name: "invalid instances",
+			files: map[string]string{"config.yaml": `
+default_instance: prod
+instances:
+  prod:
+    token: a
+  "bad name":
+    url: https://bad.example.com
+    token: b
+  staging:
+    url: ftp://staging.example.com
+    tls:
+      cert: /etc/tls/cert.pem
+`},
+			args: []string{"-config", "{dir}/config.yaml"},
+			wantErrs: []string{
+				`invalid instance name "bad name"`,
+				"instance prod: the SUSE Observability URL is required",
+				"instance staging: invalid SUSE Observability URL",
+				"instance staging: a SUSE Observability token is required",
+				"instance staging: both a TLS client certificate and a TLS client key are needed",
+			},
+		},
+		{
+			name:     "TLS certificate without a key",
+			args:     []string{"-url", "https://flag.example.com", "-token", "secret", "-tls-cert", "/etc/tls/cert.pem"},
+			wantErrs: []string{"both a TLS client certificate and a TLS client key are needed"},
+		},
+		{
+			name: "token passthrough",
+			args: []string{"-url", "https://flag.example.com", "-http", ":8080", "-token-passthrough"},
+			check: func(t *testing.T, cfg *Config) {
+				if !cfg.TokenPassthrough || cfg.Token != "" {
+					t.Errorf("config = %+v, want token passthrough without a token", cfg)
+				}
+			},
+		},
+		{
+			name:     "token passthrough without HTTP",
+			args:     []string{"-url", "https://flag.example.com", "-token-passthrough"},
+			wantErrs: []string{"token passthrough needs the HTTP transport"},
+		},
+		{
+			name:     "token passthrough with instances",
+			files:    map[string]string{"config.yaml": "url: https://main.example.com\nhttp: :8080\ntoken_passthrough: true\ninstances:\n  staging:\n    url: https://staging.example.com\n    token: a\n"},
+			args:     []string{"-config", "{dir}/config.yaml"},
+			wantErrs: []string{"token passthrough only supports a single instance"},
+		},
+		{
+			name: "OAuth",
+			env: map[string]string{
+				"SUSE_OBSERVABILITY_OAUTH_RESOURCE": "https://mcp.example.com/mcp",
+				"SUSE_OBSERVABILITY_OAUTH_ISSUER":   "https://auth.example.com",
+				"SUSE_OBSERVABILITY_OAUTH_SCOPES":   "mcp:read, mcp:write",
+			},
+			args: []string{"-url", "https://flag.example.com", "-token", "secret", "-http", ":8080"},
+			check: func(t *testing.T, cfg *Config) {
+				if !cfg.OAuth.Enabled() || !slices.Equal(cfg.OAuth.Scopes, []string{"mcp:read", "mcp:write"}) {
+					t.Errorf("OAuth = %+v, want enabled with the scopes of the environment", cfg.OAuth)
+				}
+			},
+		},
+		{
+			name:     "OAuth without HTTP",
+			args:     []string{"-url", "https://flag.example.com", "-token", "secret", "-oauth-resource", "https://mcp.example.com/mcp", "-oauth-issuer", "https://auth.example.com"},
+			wantErrs: []string{"OAuth needs the HTTP transport"},
+		},
+		{
+			name:     "OAuth without a resource",
+			args:     []string{"-url", "https://flag.example.com", "-token", "secret", "-http", ":8080", "-oauth-issuer", "https://auth.example.com"},
+			wantErrs: []string{"OAuth needs the resource URL of this server"},
+		},
+		{
+			name:     "OAuth without an issuer",
+			args:     []string{"-url", "https://flag.example.com", "-token", "secret", "-http", ":8080", "-oauth-resource", "https://mcp.example.com/mcp", "-oauth-scopes", "mcp:read"},
+			wantErrs: []string{"OAuth needs an issuer (-oauth-issuer) or a JWKS URL (-oauth-jwks-url)"},
+		},
+		{
+			name:     "OAuth with an invalid URL",
+			args:     []string{"-url", "https://flag.example.com", "-token", "secret", "-http", ":8080", "-oauth-resource", "https://mcp.example.com/mcp", "-oauth-jwks-url", "auth.example.com/jwks"},
+			wantErrs: []string{"invalid OAuth JWKS URL"},
+		},
+		{
+			name:     "negative timeouts and conflicting tools",
+			args:     []string{"-url", "https://flag.example.com", "-token", "secret", "-request-timeout", "-1s", "-tools", "getMetrics", "-disabled-tools", "getMetrics"},
+			wantErrs: []string{"the request timeout cannot be negative", "tool getMetrics is both enabled and disabled"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			expand := func(s string) string { return strings.ReplaceAll(s, "{dir}", dir) }
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(expand(content)), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			getenv := func(key string) string { return expand(tt.env[key]) }
+			args := make([]string, len(tt.args))
+			for i, arg := range tt.args {
+				args[i] = expand(arg)
+			}
+
+			cfg, err := Load(args, getenv)
+			if len(tt.wantErrs) > 0 {
+				if err == nil {
+					t.Fatalf("Load(%q) = %+v, want an error", args, cfg)
+				}
+				for _, want := range tt.wantErrs {
+					if !strings.Contains(err.Error(), expand(want)) {
+						t.Errorf("Load(%q) error = %q, want it to contain %q", args, err, expand(want))
+					}
+				}
+				for _, notWant := range tt.notWantErrs {
+					if strings.Contains(err.Error(), notWant) {
+						t.Errorf("Load(%q) error = %q, want it not to contain %q", args, err, notWant)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load(%q) failed: %v", args, err)
+			}
+			// Checks compare paths with {dir} unexpanded
+			cfg.TokenFile = strings.ReplaceAll(cfg.TokenFile, dir, "{dir}")
+			tt.check(t, cfg)
+		})
+	}
+}
+
+func TestLoadHelp(t *testing.T) {
+	if _, err := Load([]string{"-h"}, func(string) string { return "" }); !errors.Is(err, flag.ErrHelp) {
+		t.Errorf("Load(-h) error = %v, want flag.ErrHelp", err)
+	}
+}
+
+func TestQueryTimeout(t *testing.T) {
+	tests := []struct {
+		timeout time.Duration
+		want    string
+	}{
+		{timeout: 0, want: ""},
+		{timeout: 30 * time.Second, want: "30s"},
+		{timeout: 2 * time.Minute, want: "120s"},
+		{timeout: 1500 * time.Millisecond, want: "1500ms"},
+	}
+	for _, tt := range tests {
+		cfg := Config{Timeouts: Timeouts{Query: tt.timeout}}
+		if got := cfg.QueryTimeout(); got != tt.want {
+			t.Errorf("QueryTimeout() with %s = %q, want %q", tt.timeout, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,412 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"suse-observability-mcp/client/suseobservability"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	anomalyMaxPoints    = 500
+	defaultAnomalyLimit = 50
+)
+
+const (
+	anomalyMethodZScore   = "zscore"
+	anomalyMethodMAD      = "mad"
+	anomalyMethodSeasonal = "seasonal"
+)
+
+type DetectAnomaliesParams struct {
+	InstanceParams
+
+	Query     string  `json:"query" jsonschema:"The PromQL query to analyze"`
+	Start     string  `json:"start,omitempty" jsonschema:"Start time: 'now', duration ago (e.g. '1h', '7d'), 'now-2h', 'today', 'yesterday', RFC3339 or Unix timestamp,default=6h"`
+	End       string  `json:"end,omitempty" jsonschema:"End time: 'now', duration ago (e.g. '1h', '7d'), 'now-2h', 'today', 'yesterday', RFC3339 or Unix timestamp,default=now"`
+	Step      string  `json:"step,omitempty" jsonschema:"Query resolution step width in duration format or float number of seconds. Chosen from the time range when empty."`
+	Method    string  `json:"method,omitempty" jsonschema:"Detection method: 'mad' (robust distance to the median), 'zscore' (distance to the mean in standard deviations) or 'seasonal' (distance to the same window one season ago),default=mad"`
+	Season    string  `json:"season,omitempty" jsonschema:"Season of the seasonal method: '1d' compares against the same window a day ago, '1w' a week ago,default=1d"`
+	Threshold float64 `json:"threshold,omitempty" jsonschema:"Score from which a point is anomalous. Defaults to 3 for zscore and 3.5 for mad and seasonal."`
+	Limit     int     `json:"limit,omitempty" jsonschema:"Maximum number of anomalous intervals to return, most severe first,default=50"`
+}
+
+type AnomalyInterval struct {
+	Labels    map[string]string `json:"labels,omitempty"`
+	Start     time.Time         `json:"start"`
+	End       time.Time         `json:"end"`
+	Points    int               `json:"points"`
+	Direction string            `json:"direction" jsonschema:"'spike' when the values are above the expected value, 'drop' when below"`
+	Peak      float64           `json:"peak" jsonschema:"The value of the most anomalous point"`
+	Expected  float64           `json:"expected" jsonschema:"The value expected at the most anomalous point"`
+	Score     float64           `json:"score" jsonschema:"The score of the most anomalous point, negative for drops"`
+	Severity  string            `json:"severity" jsonschema:"'low', 'medium' or 'high', relative to the threshold"`
+}
+
+type AnomaliesResult struct {
+	Query     string            `json:"query"`
+	Method    string            `json:"method"`
+	Season    string            `json:"season,omitempty"`
+	Threshold float64           `json:"threshold"`
+	Start     time.Time         `json:"start"`
+	End       time.Time         `json:"end"`
+	Step      string            `json:"step"`
+	Series    int               `json:"series"`
+	Total     int               `json:"total"`
+	Intervals []AnomalyInterval `json:"intervals"`
+}
+
+// scoredPoint is a metric point with the value expected by the detection method and its distance to it
+type scoredPoint struct {
+	suseobservability.MetricPoint
+	Expected float64
+	Score    float64
+}
+
+// DetectAnomalies flags the points of every series that deviate from the expected values and groups them in intervals
+func (t tool) DetectAnomalies(ctx context.Context, request *mcp.CallToolRequest, params DetectAnomaliesParams) (*mcp.CallToolResult, *AnomaliesResult, error) {
+	t, err := t.instance(params.Instance)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	start, end, err := parseTimeRange(params.Start, params.End, t.windows["detectAnomalies"])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var step time.Duration
+	if params.Step == "" {
+		step = autoStep(start, end, anomalyMaxPoints)
+	} else {
+		step, err = parseStep(params.Step)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	method := params.Method
+	if method == "" {
+		method = anomalyMethodMAD
+	}
+	threshold := params.Threshold
+	var season time.Duration
+	seasonParam := params.Season
+	switch method {
+	case anomalyMethodZScore:
+		if threshold <= 0 {
+			threshold = 3
+		}
+	case anomalyMethodMAD:
+		if threshold <= 0 {
+			threshold = 3.5
+		}
+	case anomalyMethodSeasonal:
+		if threshold <= 0 {
+			threshold = 3.5
+		}
+		if seasonParam == "" {
+			seasonParam = "1d"
+		}
+		season, err = parseDuration(seasonParam)
+		if err != nil || season <= 0 {
+			return nil, nil, fmt.Errorf("invalid season '%s'. Must be a duration like '1d' or '1w'", params.Season)
+		}
+	default:
+		return nil, nil, fmt.Errorf("invalid method '%s'. Must be 'zscore', 'mad' or 'seasonal'", params.Method)
+	}
+
+	limit := params.Limit
+	if limit <= 0 {
+		limit = defaultAnomalyLimit
+	}
+
+	result, err := t.client.QueryRangeMetric(ctx, params.Query, start, end, formatStep(step), t.queryTimeout)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query range metric: %w", err)
+	}
+
+	// The seasonal baseline is the same query over the same window one season earlier
+	var baselines map[string]suseobservability.MetricResult
+	if method == anomalyMethodSeasonal {
+		baseline, err := t.client.QueryRangeMetric(ctx, params.Query, start.Add(-season), end.Add(-season), formatStep(step), t.queryTimeout)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to query seasonal baseline: %w", err)
+		}
+		baselines = make(map[string]suseobservability.MetricResult, len(baseline.Data.Result))
+		for _, res := range baseline.Data.Result {
+			baselines[seriesKey(res.Labels)] = res
+		}
+	}
+
+	intervals := []AnomalyInterval{}
+	withoutBaseline := 0
+	for _, res := range result.Data.Result {
+		points := finitePoints(res.Points)
+		var scored []scoredPoint
+		switch method {
+		case anomalyMethodZScore:
+			scored = zScores(points)
+		case anomalyMethodMAD:
+			scored = madScores(points)
+		case anomalyMethodSeasonal:
+			b, ok := baselines[seriesKey(res.Labels)]
+			if !ok {
+				withoutBaseline++
+				continue
+			}
+			scored = seasonalScores(points, finitePoints(b.Points), season)
+		}
+		intervals = append(intervals, findAnomalies(scored, threshold, res.Labels)...)
+	}
+
+	// Most severe first
+	sort.SliceStable(intervals, func(i, j int) bool {
+		return math.Abs(intervals[i].Score) > math.Abs(intervals[j].Score)
+	})
+	total := len(intervals)
+	if total > limit {
+		intervals = intervals[:limit]
+	}
+
+	structured := &AnomaliesResult{
+		Query:     params.Query,
+		Method:    method,
+		Threshold: threshold,
+		Start:     start,
+		End:       end,
+		Step:      formatStep(step),
+		Series:    len(result.Data.Result),
+		Total:     total,
+		Intervals: intervals,
+	}
+	if method == anomalyMethodSeasonal {
+		structured.Season = seasonParam
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: formatAnomalies(structured, withoutBaseline),
+			},
+		},
+	}, structured, nil
+}
+
+// zScores scores every point by its distance to the mean, in standard deviations
+func zScores(points []suseobservability.MetricPoint) []scoredPoint {
+	values := pointValues(points)
+	m, sd := mean(values), stddev(values)
+	scored := make([]scoredPoint, 0, len(points))
+	for _, p := range points {
+		s := scoredPoint{MetricPoint: p, Expected: m}
+		if sd > 0 {
+			s.Score = (p.Value - m) / sd
+		}
+		scored = append(scored, s)
+	}
+	return scored
+}
+
+// madScores scores every point with the modified z-score: the distance to the median scaled by the
+// median absolute deviation, so that the spikes being looked for don't inflate the spread
+func madScores(points []suseobservability.MetricPoint) []scoredPoint {
+	values := pointValues(points)
+	center, scale := robustSpread(values)
+	scored := make([]scoredPoint, 0, len(points))
+	for _, p := range points {
+		s := scoredPoint{MetricPoint: p, Expected: center}
+		if scale > 0 {
+			s.Score = (p.Value - center) / scale
+		}
+		scored = append(scored, s)
+	}
+	return scored
+}
+
+// seasonalScores compares every point with the point one season earlier. The differences are scored
+// with the modified z-score so that a constant shift between both windows isn't flagged.
+// Points without a baseline value are left out.
+func seasonalScores(points, baseline []suseobservability.MetricPoint, season time.Duration) []scoredPoint {
+	previous := make(map[int64]float64, len(baseline))
+	for _, p := range baseline {
+		previous[p.Timestamp+int64(season/time.Second)] = p.Value
+	}
+
+	var matched []scoredPoint
+	var diffs []float64
+	for _, p := range points {
+		b, ok := previous[p.Timestamp]
+		if !ok {
+			continue
+		}
+		matched = append(matched, scoredPoint{MetricPoint: p, Expected: b})
+		diffs = append(diffs, p.Value-b)
+	}
+
+	// The expected value is the previous value shifted by the usual difference between both windows
+	center, scale := robustSpread(diffs)
+	for i := range matched {
+		matched[i].Expected += center
+		if scale > 0 {
+			matched[i].Score = (diffs[i] - center) / scale
+		}
+	}
+	return matched
+}
+
+// robustSpread returns the median of values and the median absolute deviation scaled to be comparable
+// with a standard deviation. It falls back to the mean absolute deviation when more than half of the
+// values are equal.
+func robustSpread(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	center := median(values)
+	deviations := make([]float64, 0, len(values))
+	for _, v := range values {
+		deviations = append(deviations, math.Abs(v-center))
+	}
+	if mad := median(deviations); mad > 0 {
+		return center, 1.4826 * mad
+	}
+	return center, 1.2533 * mean(deviations)
+}
+
+// findAnomalies groups consecutive points scoring above the threshold in the same direction into intervals
+func findAnomalies(scored []scoredPoint, threshold float64, labels map[string]string) []AnomalyInterval {
+	var intervals []AnomalyInterval
+	var current *AnomalyInterval
+	last := -1
+	for i, s := range scored {
+		if math.Abs(s.Score) < threshold {
+			continue
+		}
+		direction := "spike"
+		if s.Score < 0 {
+			direction = "drop"
+		}
+		ts := time.Unix(s.Timestamp, 0)
+		if current == nil || last != i-1 || current.Direction != direction {
+			intervals = append(intervals, AnomalyInterval{Labels: labels, Start: ts, Direction: direction})
+			current = &intervals[len(intervals)-1]
+		}
+		current.End = ts
+		current.Points++
+		if math.Abs(s.Score) > math.Abs(current.Score) {
+			current.Peak = s.Value
+			current.Expected = s.Expected
+			current.Score = s.Score
+			current.Severity = anomalySeverity(s.Score, threshold)
+		}
+		last = i
+	}
+	return intervals
+}
+
+func anomalySeverity(score, threshold float64) string {
+	switch a := math.Abs(score); {
+	case a >= 2*threshold:
+		return "high"
+	case a >= 1.5*threshold:
+		return "medium"
+	default:
+		return "low"
+	}
+}
+
+func pointValues(points []suseobservability.MetricPoint) []float64 {
+	values := make([]float64, 0, len(points))
+	for _, p := range points {
+		values = append(values, p.Value)
+	}
+	return values
+}
+
+// seriesKey identifies a series by its sorted labels
+func seriesKey(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%q", k, labels[k]))
+	}
+	return strings.Join(parts, ",")
+}
+
+func formatAnomalies(res *AnomaliesResult, withoutBaseline int) string {
+	method := res.Method
+	if res.Season != "" {
+		method = fmt.Sprintf("%s, season %s", method, res.Season)
+	}
+
+	var sb strings.Builder
+	if res.Total == 0 {
+		sb.WriteString(fmt.Sprintf("No anomalies found in %d series from %s to %s (method %s, threshold %.1f, step %s) for query: %s\n",
+			res.Series, res.Start.Format(time.RFC3339), res.End.Format(time.RFC3339), method, res.Threshold, res.Step, res.Query))
+	} else {
+		sb.WriteString(fmt.Sprintf("Found %d anomalous interval(s) in %d series from %s to %s (method %s, threshold %.1f, step %s)",
+			res.Total, res.Series, res.Start.Format(time.RFC3339), res.End.Format(time.RFC3339), method, res.Threshold, res.Step))
+		if len(res.Intervals) < res.Total {
+			sb.WriteString(fmt.Sprintf(", showing the %d most severe", len(res.Intervals)))
+		}
+		sb.WriteString(":\n\n")
+
+		labelKeys := make(map[string]bool)
+		for _, in := range res.Intervals {
+			for k := range in.Labels {
+				if k != "__name__" {
+					labelKeys[k] = true
+				}
+			}
+		}
+		sortedKeys := make([]string, 0, len(labelKeys))
+		for k := range labelKeys {
+			sortedKeys = append(sortedKeys, k)
+		}
+		sort.Strings(sortedKeys)
+
+		// Header
+		sb.WriteString("| Severity | Start | End | Points | Direction | Peak | Expected | Score |")
+		for _, k := range sortedKeys {
+			sb.WriteString(fmt.Sprintf(" %s |", k))
+		}
+		sb.WriteString("\n")
+
+		// Separator
+		sb.WriteString("|---|---|---|---|---|---|---|---|")
+		for range sortedKeys {
+			sb.WriteString("---|")
+		}
+		sb.WriteString("\n")
+
+		// Data rows
+		for _, in := range res.Intervals {
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %d | %s | %.4f | %.4f | %+.2f |", in.Severity,
+				in.Start.Format(time.RFC3339), in.End.Format(time.RFC3339), in.Points, in.Direction, in.Peak, in.Expected, in.Score))
+			for _, k := range sortedKeys {
+				val := in.Labels[k]
+				if val == "" {
+					val = "-"
+				}
+				sb.WriteString(fmt.Sprintf(" %s |", val))
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	if withoutBaseline > 0 {
+		sb.WriteString(fmt.Sprintf("\n%d series had no data one season earlier and were not analyzed.\n", withoutBaseline))
+	}
+
+	return sb.String()
+}
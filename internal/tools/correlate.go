@@ -0,0 +1,425 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"suse-observability-mcp/client/suseobservability"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	correlationMaxPoints       = 300
+	maxCorrelationSeries       = 200
+	maxCorrelationNeighbors    = 20
+	minCorrelationPoints       = 10
+	defaultCorrelationsLimit   = 10
+	defaultCorrelationMaxLag   = "15m"
+	correlationTransformDiff   = "diff"
+	correlationTransformNone   = "none"
+	correlationRelationLeads   = "leads"
+	correlationRelationLags    = "lags"
+	correlationRelationAligned = "simultaneous"
+)
+
+type CorrelateMetricsParams struct {
+	InstanceParams
+
+	Target      string   `json:"target" jsonschema:"The PromQL query of the metric to explain. Several series are averaged into one."`
+	Candidates  []string `json:"candidates,omitempty" jsonschema:"PromQL queries of the candidate metrics"`
+	ComponentID int64    `json:"component_id,omitempty" jsonschema:"Also use the bound metrics of the neighbors of this component as candidates"`
+	Levels      string   `json:"levels,omitempty" jsonschema:"Number of neighbor levels (1-14) whose bound metrics are used,default=1"`
+	Start       string   `json:"start,omitempty" jsonschema:"Start time: 'now', duration ago (e.g. '1h', '7d'), 'now-2h', 'today', 'yesterday', RFC3339 or Unix timestamp,default=2h"`
+	End         string   `json:"end,omitempty" jsonschema:"End time: 'now', duration ago (e.g. '1h', '7d'), 'now-2h', 'today', 'yesterday', RFC3339 or Unix timestamp,default=now"`
+	Step        string   `json:"step,omitempty" jsonschema:"Query resolution step width in duration format or float number of seconds, at least 1s. Chosen from the time range when empty."`
+	MaxLag      string   `json:"max_lag,omitempty" jsonschema:"Largest lead or lag to look for (e.g. '15m'),default=15m"`
+	Transform   string   `json:"transform,omitempty" jsonschema:"'diff' correlates the changes between consecutive points, which avoids matching unrelated trends, 'none' correlates the values,default=diff"`
+	Limit       int      `json:"limit,omitempty" jsonschema:"Maximum number of correlated series to return,default=10"`
+}
+
+type MetricCorrelation struct {
+	Query       string            `json:"query"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Component   string            `json:"component,omitempty" jsonschema:"The neighbor the bound metric belongs to"`
+	Metric      string            `json:"metric,omitempty" jsonschema:"The name of the bound metric"`
+	Correlation float64           `json:"correlation" jsonschema:"Pearson correlation at the best lag, negative when the series move in opposite directions"`
+	LagSeconds  int64             `json:"lag_seconds" jsonschema:"How long the candidate moves before the target, negative when it moves after"`
+	Relation    string            `json:"relation" jsonschema:"'leads' when the candidate moves first, 'lags' when it moves after the target, 'simultaneous' otherwise"`
+	Points      int               `json:"points"`
+}
+
+type CorrelationResult struct {
+	Target       string              `json:"target"`
+	Start        time.Time           `json:"start"`
+	End          time.Time           `json:"end"`
+	Step         string              `json:"step"`
+	MaxLag       string              `json:"max_lag"`
+	Transform    string              `json:"transform"`
+	Series       int                 `json:"series" jsonschema:"Number of candidate series compared with the target"`
+	Total        int                 `json:"total"`
+	Correlations []MetricCorrelation `json:"correlations"`
+	Errors       []string            `json:"errors,omitempty" jsonschema:"Candidate queries that failed and neighbors whose bound metrics could not be listed"`
+}
+
+// correlationCandidate is a candidate query and where it comes from
+type correlationCandidate struct {
+	query      string
+	component  string
+	metric     string
+	unresolved []string
+}
+
+// CorrelateMetrics ranks candidate series by their lagged cross-correlation with a target metric
+func (t tool) CorrelateMetrics(ctx context.Context, request *mcp.CallToolRequest, params CorrelateMetricsParams) (*mcp.CallToolResult, *CorrelationResult, error) {
+	t, err := t.instance(params.Instance)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	start, end, err := parseTimeRange(params.Start, params.End, t.windows["correlateMetrics"])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var step time.Duration
+	if params.Step == "" {
+		step = autoStep(start, end, correlationMaxPoints)
+	} else {
+		step, err = parseStep(params.Step)
+		if err != nil {
+			return nil, nil, err
+		}
+		// Points carry whole-second timestamps, they cannot be placed on a finer grid
+		if step < time.Second {
+			return nil, nil, fmt.Errorf("invalid step '%s'. Must be at least 1s", params.Step)
+		}
+	}
+
+	maxLagParam := params.MaxLag
+	if maxLagParam == "" {
+		maxLagParam = defaultCorrelationMaxLag
+	}
+	maxLag, err := parseDuration(maxLagParam)
+	if err != nil || maxLag < 0 {
+		return nil, nil, fmt.Errorf("invalid max_lag '%s'. Must be a duration like '15m'", params.MaxLag)
+	}
+
+	transform := params.Transform
+	if transform == "" {
+		transform = correlationTransformDiff
+	}
+	if transform != correlationTransformDiff && transform != correlationTransformNone {
+		return nil, nil, fmt.Errorf("invalid transform '%s'. Must be 'diff' or 'none'", params.Transform)
+	}
+
+	limit := params.Limit
+	if limit <= 0 {
+		limit = defaultCorrelationsLimit
+	}
+
+	candidates := make([]correlationCandidate, 0, len(params.Candidates))
+	for _, q := range params.Candidates {
+		if q = strings.TrimSpace(q); q != "" {
+			candidates = append(candidates, correlationCandidate{query: q})
+		}
+	}
+	var neighborErrs []string
+	if params.ComponentID != 0 {
+		var bound []correlationCandidate
+		bound, neighborErrs, err = t.neighborBoundQueries(ctx, params.ComponentID, params.Levels, start, end, step)
+		if err != nil {
+			return nil, nil, err
+		}
+		candidates = append(candidates, bound...)
+	}
+	if len(candidates) == 0 {
+		if len(neighborErrs) > 0 {
+			return nil, nil, fmt.Errorf("no candidate queries, the bound metrics of every neighbor failed: %s", strings.Join(neighborErrs, "; "))
+		}
+		return nil, nil, fmt.Errorf("at least one candidate query or a component_id must be provided")
+	}
+
+	// The target series on the step grid of the window
+	targetRes, err := t.client.QueryRangeMetric(ctx, params.Target, start, end, formatStep(step), t.queryTimeout)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query target metric: %w", err)
+	}
+	if len(targetRes.Data.Result) == 0 {
+		return nil, nil, fmt.Errorf("no data found for target query: %s", params.Target)
+	}
+	target := averageSeries(targetRes.Data.Result, start, end, step)
+	if transform == correlationTransformDiff {
+		target = differences(target)
+	}
+	lagSteps := int(maxLag / step)
+
+	result := &CorrelationResult{
+		Target:       params.Target,
+		Start:        start,
+		End:          end,
+		Step:         formatStep(step),
+		MaxLag:       maxLagParam,
+		Transform:    transform,
+		Correlations: []MetricCorrelation{},
+		Errors:       neighborErrs,
+	}
+
+	for _, c := range candidates {
+		if result.Series >= maxCorrelationSeries {
+			break
+		}
+		if len(c.unresolved) > 0 {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: unresolved variables %s", c.query, strings.Join(c.unresolved, ", ")))
+			continue
+		}
+		res, err := t.client.QueryRangeMetric(ctx, c.query, start, end, formatStep(step), t.queryTimeout)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", c.query, err))
+			continue
+		}
+		for _, series := range res.Data.Result {
+			if result.Series >= maxCorrelationSeries {
+				break
+			}
+			result.Series++
+			values := alignSeries(series.Points, start, end, step)
+			if transform == correlationTransformDiff {
+				values = differences(values)
+			}
+			corr, lag, points := laggedCorrelation(target, values, lagSteps)
+			if points < minCorrelationPoints {
+				continue
+			}
+			mc := MetricCorrelation{
+				Query:       c.query,
+				Labels:      series.Labels,
+				Component:   c.component,
+				Metric:      c.metric,
+				Correlation: corr,
+				LagSeconds:  int64(math.Round(float64(lag) * step.Seconds())),
+				Relation:    correlationRelationAligned,
+				Points:      points,
+			}
+			switch {
+			case lag > 0:
+				mc.Relation = correlationRelationLeads
+			case lag < 0:
+				mc.Relation = correlationRelationLags
+			}
+			result.Correlations = append(result.Correlations, mc)
+		}
+	}
+
+	sort.SliceStable(result.Correlations, func(i, j int) bool {
+		return math.Abs(result.Correlations[i].Correlation) > math.Abs(result.Correlations[j].Correlation)
+	})
+	result.Total = len(result.Correlations)
+	if result.Total > limit {
+		result.Correlations = result.Correlations[:limit]
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: formatCorrelations(result),
+			},
+		},
+	}, result, nil
+}
+
+// neighborBoundQueries collects the bound metric queries of the neighbors of a component, leaving the component itself out.
+// A neighbor whose bound metrics cannot be listed is skipped, its failure being returned with the queries.
+func (t tool) neighborBoundQueries(ctx context.Context, componentID int64, levels string, start, end time.Time, step time.Duration) ([]correlationCandidate, []string, error) {
+	if levels == "" {
+		levels = "1"
+	}
+	query := fmt.Sprintf("withNeighborsOf(components = (id = %d), levels = \"%s\", direction = \"both\")", componentID, levels)
+	neighbors, err := t.client.SnapShotTopologyQuery(ctx, query)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query topology (STQL: %s): %w", query, err)
+	}
+
+	var candidates []correlationCandidate
+	var errs []string
+	lookups := 0
+	for _, n := range neighbors {
+		if n.ID == componentID {
+			continue
+		}
+		if lookups >= maxCorrelationNeighbors {
+			break
+		}
+		lookups++
+		bound, err := t.client.GetBoundMetricsWithData(ctx, n.ID, start, end)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: failed to list bound metrics: %v", n.Name, err))
+			continue
+		}
+		for _, bm := range bound.BoundMetrics {
+			for _, bq := range bm.BoundQueries {
+				expanded, unresolved := expandQueryTemplate(bq.Expression, step, &n)
+				candidates = append(candidates, correlationCandidate{
+					query:      expanded,
+					component:  n.Name,
+					metric:     bm.Name,
+					unresolved: unresolved,
+				})
+			}
+		}
+	}
+	return candidates, errs, nil
+}
+
+// alignSeries places the points of a series on the step grid of the window, NaN marking missing points
+func alignSeries(points []suseobservability.MetricPoint, start, end time.Time, step time.Duration) []float64 {
+	stepSeconds := step.Seconds()
+	n := int(end.Sub(start)/step) + 1
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = math.NaN()
+	}
+	for _, p := range points {
+		i := int(math.Round(float64(p.Timestamp-start.Unix()) / stepSeconds))
+		if i >= 0 && i < n {
+			values[i] = p.Value
+		}
+	}
+	return values
+}
+
+// averageSeries averages several series into one on the step grid of the window
+func averageSeries(series []suseobservability.MetricResult, start, end time.Time, step time.Duration) []float64 {
+	var sums, counts []float64
+	for _, s := range series {
+		values := alignSeries(s.Points, start, end, step)
+		if sums == nil {
+			sums = make([]float64, len(values))
+			counts = make([]float64, len(values))
+		}
+		for i, v := range values {
+			if !math.IsNaN(v) && !math.IsInf(v, 0) {
+				sums[i] += v
+				counts[i]++
+			}
+		}
+	}
+	for i := range sums {
+		if counts[i] == 0 {
+			sums[i] = math.NaN()
+		} else {
+			sums[i] /= counts[i]
+		}
+	}
+	return sums
+}
+
+// differences returns the change between every point and the previous one
+func differences(values []float64) []float64 {
+	if len(values) == 0 {
+		return values
+	}
+	diffs := make([]float64, len(values))
+	diffs[0] = math.NaN()
+	for i := 1; i < len(values); i++ {
+		diffs[i] = values[i] - values[i-1]
+	}
+	return diffs
+}
+
+// laggedCorrelation returns the strongest correlation between target[i] and candidate[i-lag] for lags
+// within maxLag steps, the lag it was found at and the number of points it was computed on. A positive
+// lag means the candidate moves before the target.
+func laggedCorrelation(target, candidate []float64, maxLag int) (float64, int, int) {
+	var best float64
+	var bestLag, bestPoints int
+	for lag := -maxLag; lag <= maxLag; lag++ {
+		var xs, ys []float64
+		for i := range target {
+			j := i - lag
+			if j < 0 || j >= len(candidate) {
+				continue
+			}
+			x, y := target[i], candidate[j]
+			if math.IsNaN(x) || math.IsInf(x, 0) || math.IsNaN(y) || math.IsInf(y, 0) {
+				continue
+			}
+			xs = append(xs, x)
+			ys = append(ys, y)
+		}
+		if len(xs) < minCorrelationPoints {
+			continue
+		}
+		// Prefer the smallest lag when correlations are equal
+		if r := pearson(xs, ys); math.Abs(r) > math.Abs(best) ||
+			(math.Abs(r) == math.Abs(best) && abs(lag) < abs(bestLag)) || bestPoints == 0 {
+			best, bestLag, bestPoints = r, lag, len(xs)
+		}
+	}
+	return best, bestLag, bestPoints
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func formatCorrelations(res *CorrelationResult) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Correlation of %d candidate series with %s from %s to %s (step %s, max lag %s, transform %s)",
+		res.Series, res.Target, res.Start.Format(time.RFC3339), res.End.Format(time.RFC3339), res.Step, res.MaxLag, res.Transform))
+
+	if len(res.Correlations) == 0 {
+		sb.WriteString(":\n\nNo candidate series had enough points in common with the target.\n")
+	} else {
+		if len(res.Correlations) < res.Total {
+			sb.WriteString(fmt.Sprintf(", showing the %d strongest of %d", len(res.Correlations), res.Total))
+		}
+		sb.WriteString(". A candidate that leads moved before the target:\n\n")
+		sb.WriteString("| Rank | Correlation | Lead/Lag | Component | Metric | Query | Labels |\n")
+		sb.WriteString("|---|---|---|---|---|---|---|\n")
+		for i, c := range res.Correlations {
+			lag := time.Duration(c.LagSeconds) * time.Second
+			timing := c.Relation
+			switch c.Relation {
+			case correlationRelationLeads:
+				timing = fmt.Sprintf("leads by %s", lag)
+			case correlationRelationLags:
+				timing = fmt.Sprintf("lags by %s", -lag)
+			}
+			component := c.Component
+			if component == "" {
+				component = "-"
+			}
+			metric := c.Metric
+			if metric == "" {
+				metric = "-"
+			}
+			labels := make([]string, 0, len(c.Labels))
+			for _, k := range sortedLabelKeys([]suseobservability.MetricResult{{Labels: c.Labels}}) {
+				labels = append(labels, fmt.Sprintf("%s=%s", k, c.Labels[k]))
+			}
+			labelsCol := strings.Join(labels, ", ")
+			if labelsCol == "" {
+				labelsCol = "-"
+			}
+			sb.WriteString(fmt.Sprintf("| %d | %+.3f | %s | %s | %s | `%s` | %s |\n",
+				i+1, c.Correlation, timing, component, metric, c.Query, labelsCol))
+		}
+	}
+
+	if len(res.Errors) > 0 {
+		sb.WriteString(fmt.Sprintf("\n%d candidate query(ies) or neighbor(s) failed:\n\n", len(res.Errors)))
+		for _, e := range res.Errors {
+			sb.WriteString(fmt.Sprintf("- %s\n", e))
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,57 @@
+package tools
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"suse-observability-mcp/client/suseobservability"
+)
+
+func TestAlignSeries(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+
+	tests := []struct {
+		name   string
+		step   time.Duration
+		end    time.Duration
+		points []suseobservability.MetricPoint
+		want   []float64
+	}{
+		{
+			name:   "whole seconds",
+			step:   time.Minute,
+			end:    3 * time.Minute,
+			points: []suseobservability.MetricPoint{{Timestamp: start.Unix(), Value: 1}, {Timestamp: start.Unix() + 120, Value: 3}},
+			want:   []float64{1, math.NaN(), 3, math.NaN()},
+		},
+		{
+			// 1.5s truncated to 1s would place the point at 3s on the fourth slot instead of the third
+			name:   "fractional seconds",
+			step:   1500 * time.Millisecond,
+			end:    6 * time.Second,
+			points: []suseobservability.MetricPoint{{Timestamp: start.Unix() + 3, Value: 2}, {Timestamp: start.Unix() + 6, Value: 4}},
+			want:   []float64{math.NaN(), math.NaN(), 2, math.NaN(), 4},
+		},
+		{
+			name:   "outside the window",
+			step:   time.Second,
+			end:    time.Second,
+			points: []suseobservability.MetricPoint{{Timestamp: start.Unix() - 1, Value: 1}, {Timestamp: start.Unix() + 2, Value: 1}},
+			want:   []float64{math.NaN(), math.NaN()},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := alignSeries(tt.points, start, start.Add(tt.end), tt.step)
+			if len(got) != len(tt.want) {
+				t.Fatalf("alignSeries() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] && !(math.IsNaN(got[i]) && math.IsNaN(tt.want[i])) {
+					t.Fatalf("alignSeries() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package tools
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"suse-observability-mcp/client/suseobservability"
+)
+
+const defaultMaxPoints = 120
+
+// minMaxPoints is the smallest max_points, LTTB keeping the first and last points and one point per bucket in between
+const minMaxPoints = 3
+
+// summaryMaxPoints is the point budget of the automatic step of summarized series. It is finer than the
+// max_points of raw output, so the extremes and percentiles catch short spikes.
+const summaryMaxPoints = 1500
+
+// niceSteps are the resolutions picked when choosing the step automatically
+var niceSteps = []time.Duration{
+	15 * time.Second, 30 * time.Second,
+	time.Minute, 2 * time.Minute, 5 * time.Minute, 10 * time.Minute, 15 * time.Minute, 30 * time.Minute,
+	time.Hour, 2 * time.Hour, 3 * time.Hour, 6 * time.Hour, 12 * time.Hour, 24 * time.Hour,
+}
+
+// autoStep returns the smallest nice step that keeps the range within maxPoints points per series
+func autoStep(start, end time.Time, maxPoints int) time.Duration {
+	span := end.Sub(start)
+	for _, s := range niceSteps {
+		if int(span/s)+1 <= maxPoints {
+			return s
+		}
+	}
+	step := span / time.Duration(maxPoints)
+	return step.Truncate(time.Hour) + time.Hour
+}
+
+// parseMaxPoints returns the maximum number of points per series, the default when not set
+func parseMaxPoints(maxPoints int) (int, error) {
+	switch {
+	case maxPoints == 0:
+		return defaultMaxPoints, nil
+	case maxPoints < minMaxPoints:
+		return 0, fmt.Errorf("invalid max_points %d: must be at least %d", maxPoints, minMaxPoints)
+	}
+	return maxPoints, nil
+}
+
+// parseStep parses a step in duration format (e.g. '1m', '1d') or as a float number of seconds
+func parseStep(s string) (time.Duration, error) {
+	if d, err := parseDuration(s); err == nil && d > 0 {
+		return d, nil
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil && f > 0 {
+		return time.Duration(f * float64(time.Second)), nil
+	}
+	return 0, fmt.Errorf("invalid step: %s (expected a duration like '1m' or a number of seconds)", s)
+}
+
+// formatStep renders a step in the format expected by the metrics API
+func formatStep(d time.Duration) string {
+	if d%time.Second == 0 {
+		return fmt.Sprintf("%ds", int64(d/time.Second))
+	}
+	return fmt.Sprintf("%dms", d.Milliseconds())
+}
+
+// downsampleLTTB reduces the points of a series to at most threshold points with the
+// Largest-Triangle-Three-Buckets algorithm, which keeps the visual shape of the series.
+func downsampleLTTB(points []suseobservability.MetricPoint, threshold int) []suseobservability.MetricPoint {
+	if threshold >= len(points) || threshold < 3 {
+		return points
+	}
+
+	sampled := make([]suseobservability.MetricPoint, 0, threshold)
+	sampled = append(sampled, points[0])
+
+	bucketSize := float64(len(points)-2) / float64(threshold-2)
+	a := 0
+	for i := 0; i < threshold-2; i++ {
+		// Average of the next bucket, used as the third point of the triangle
+		nextStart := int(math.Floor(float64(i+1)*bucketSize)) + 1
+		nextEnd := min(int(math.Floor(float64(i+2)*bucketSize))+1, len(points))
+		var avgX, avgY float64
+		for _, p := range points[nextStart:nextEnd] {
+			avgX += float64(p.Timestamp)
+			avgY += p.Value
+		}
+		n := float64(nextEnd - nextStart)
+		avgX /= n
+		avgY /= n
+
+		// Pick the point of the current bucket forming the largest triangle
+		bucketStart := int(math.Floor(float64(i)*bucketSize)) + 1
+		bucketEnd := int(math.Floor(float64(i+1)*bucketSize)) + 1
+		ax, ay := float64(points[a].Timestamp), points[a].Value
+		maxArea := -1.0
+		next := bucketStart
+		for j := bucketStart; j < bucketEnd; j++ {
+			area := math.Abs((ax-avgX)*(points[j].Value-ay) - (ax-float64(points[j].Timestamp))*(avgY-ay))
+			if area > maxArea {
+				maxArea = area
+				next = j
+			}
+		}
+		sampled = append(sampled, points[next])
+		a = next
+	}
+
+	return append(sampled, points[len(points)-1])
+}
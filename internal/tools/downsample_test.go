@@ -0,0 +1,84 @@
+package tools
+
+import (
+	"slices"
+	"testing"
+
+	"suse-observability-mcp/client/suseobservability"
+)
+
+func TestParseMaxPoints(t *testing.T) {
+	tests := []struct {
+		maxPoints int
+		want      int
+		wantErr   bool
+	}{
+		{maxPoints: 0, want: defaultMaxPoints},
+		{maxPoints: 3, want: 3},
+		{maxPoints: 500, want: 500},
+		{maxPoints: 2, wantErr: true},
+		{maxPoints: 1, wantErr: true},
+		{maxPoints: -1, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseMaxPoints(tt.maxPoints)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseMaxPoints(%d) = %d, want an error", tt.maxPoints, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("parseMaxPoints(%d) = %d, %v, want %d", tt.maxPoints, got, err, tt.want)
+		}
+	}
+}
+
+func TestDownsampleLTTB(t *testing.T) {
+	points := make([]suseobservability.MetricPoint, 100)
+	for i := range points {
+		points[i] = suseobservability.MetricPoint{Timestamp: int64(i) * 1000, Value: float64(i % 10)}
+	}
+	// A spike the downsampled series must keep
+	points[42].Value = 1000
+
+	for _, threshold := range []int{minMaxPoints, 4, 10, 99} {
+		sampled := downsampleLTTB(points, threshold)
+		if len(sampled) != threshold {
+			t.Fatalf("downsampleLTTB(%d) returned %d points", threshold, len(sampled))
+		}
+		if sampled[0] != points[0] || sampled[len(sampled)-1] != points[len(points)-1] {
+			t.Errorf("downsampleLTTB(%d) did not keep the first and last points", threshold)
+		}
+		for i := 1; i < len(sampled); i++ {
+			if sampled[i].Timestamp <= sampled[i-1].Timestamp {
+				t.Fatalf("downsampleLTTB(%d) points are not in time order", threshold)
+			}
+		}
+		if threshold > minMaxPoints && !slices.Contains(sampled, points[42]) {
+			t.Errorf("downsampleLTTB(%d) dropped the spike", threshold)
+		}
+	}
+
+	if sampled := downsampleLTTB(points, len(points)); len(sampled) != len(points) {
+		t.Errorf("downsampleLTTB with as many points returned %d points, want %d", len(sampled), len(points))
+	}
+}
+
+func TestDownsampleSeries(t *testing.T) {
+	long := make([]suseobservability.MetricPoint, 10)
+	for i := range long {
+		long[i] = suseobservability.MetricPoint{Timestamp: int64(i), Value: float64(i)}
+	}
+	series := []suseobservability.MetricResult{{Points: long}, {Points: long[:3]}}
+
+	got, downsampled := downsampleSeries(series, minMaxPoints)
+	if downsampled != 1 {
+		t.Errorf("downsampled = %d, want 1", downsampled)
+	}
+	for i, s := range got {
+		if len(s.Points) != minMaxPoints {
+			t.Errorf("series %d has %d points, want %d", i, len(s.Points), minMaxPoints)
+		}
+	}
+}
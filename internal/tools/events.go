@@ -0,0 +1,383 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"suse-observability-mcp/client/suseobservability"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	defaultEventsLimit = 50
+	maxEventsLimit     = 500
+	eventsPageSize     = 100
+)
+
+type GetEventsParams struct {
+	InstanceParams
+	TopologyFilterParams
+
+	// Raw STQL query, used instead of the filters above when provided
+	Query string `json:"query,omitempty" jsonschema:"Raw STQL topology query (e.g., 'type = \"deployment\" AND label = \"app:checkout\"'). Overrides the other component filters."`
+
+	Start            string `json:"start,omitempty" jsonschema:"Start time: 'now', duration ago (e.g. '1h', '7d'), 'now-2h', 'today', 'yesterday', RFC3339 or Unix timestamp,default=1h"`
+	End              string `json:"end,omitempty" jsonschema:"End time: 'now', duration ago (e.g. '1h', '7d'), 'now-2h', 'today', 'yesterday', RFC3339 or Unix timestamp,default=now"`
+	Categories       string `json:"categories,omitempty" jsonschema:"Event categories (comma-separated): 'Changes', 'Deployments', 'Alerts', 'Anomalies', 'Activities', 'Others'"`
+	EventTypes       string `json:"event_types,omitempty" jsonschema:"Event types to match (comma-separated, e.g., 'HealthStateChangedEvent')"`
+	Tags             string `json:"tags,omitempty" jsonschema:"Event tags to match (comma-separated, e.g., 'namespace:prod')"`
+	Sources          string `json:"sources,omitempty" jsonschema:"Event sources to match (comma-separated, e.g., 'Kubernetes')"`
+	IncludeConnected bool   `json:"include_connected,omitempty" jsonschema:"Include events of components connected to the selected components"`
+	Limit            int    `json:"limit,omitempty" jsonschema:"Maximum number of events to return (1-500),default=50"`
+
+	// Point in time of the topology
+	At string `json:"at,omitempty" jsonschema:"Select the components as they were at this time: RFC3339 timestamp, Unix timestamp or duration ago (e.g. '2h'). Defaults to now."`
+}
+
+type GetEventParams struct {
+	InstanceParams
+
+	EventID string `json:"event_id" jsonschema:"required,The identifier of the event (from getEvents results)"`
+	Start   string `json:"start,omitempty" jsonschema:"Start of the window the event happened in: 'now', duration ago (e.g. '24h', '7d'), 'now-2h', 'today', 'yesterday', RFC3339 or Unix timestamp,default=24h"`
+	End     string `json:"end,omitempty" jsonschema:"End of the window the event happened in: 'now', duration ago (e.g. '1h', '7d'), 'now-2h', 'today', 'yesterday', RFC3339 or Unix timestamp,default=now"`
+}
+
+type Event struct {
+	ID                 string                         `json:"id"`
+	Time               time.Time                      `json:"time"`
+	Category           string                         `json:"category"`
+	Type               string                         `json:"type"`
+	Name               string                         `json:"name"`
+	Source             string                         `json:"source"`
+	ElementIdentifiers []string                       `json:"element_identifiers,omitempty"`
+	Links              []suseobservability.SourceLink `json:"links,omitempty"`
+}
+
+type EventsResult struct {
+	Query  string  `json:"query"`
+	Total  int64   `json:"total"`
+	Events []Event `json:"events"`
+}
+
+type EventComponent struct {
+	ID          int64    `json:"id"`
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	Identifiers []string `json:"identifiers,omitempty"`
+}
+
+type EventRelation struct {
+	ID        int64  `json:"id"`
+	Type      string `json:"type"`
+	Source    int64  `json:"source"`
+	Target    int64  `json:"target"`
+	Direction string `json:"direction,omitempty"`
+}
+
+type EventDetails struct {
+	Event
+	Description   string                       `json:"description,omitempty"`
+	ProcessedTime time.Time                    `json:"processed_time,omitzero"`
+	Tags          []suseobservability.EventTag `json:"tags,omitempty"`
+	Data          map[string]any               `json:"data,omitempty"`
+	Components    []EventComponent             `json:"components,omitempty"`
+	Relations     []EventRelation              `json:"relations,omitempty"`
+}
+
+// GetEvents lists topology events for the selected components over a time window
+func (t tool) GetEvents(ctx context.Context, request *mcp.CallToolRequest, params GetEventsParams) (*mcp.CallToolResult, *EventsResult, error) {
+	t, err := t.instance(params.Instance)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	query := params.Query
+	if query == "" {
+		var err error
+		query, err = buildTopologyQuery(params.TopologyFilterParams)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w (or provide a raw STQL query)", err)
+		}
+	}
+
+	start, end, err := parseTimeRange(params.Start, params.End, t.windows["getEvents"])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	at, err := parseAt(params.At)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse at time: %w", err)
+	}
+
+	categories, err := parseEventCategories(params.Categories)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	limit := params.Limit
+	if limit <= 0 {
+		limit = defaultEventsLimit
+	}
+	if limit > maxEventsLimit {
+		limit = maxEventsLimit
+	}
+
+	req := &suseobservability.EventListRequest{
+		StartTimestampMs:           start.UnixMilli(),
+		EndTimestampMs:             end.UnixMilli(),
+		TopologyQuery:              query,
+		IncludeConnectedComponents: params.IncludeConnected,
+		EventTypes:                 splitValues(params.EventTypes),
+		EventTags:                  splitValues(params.Tags),
+		EventCategories:            categories,
+		EventSources:               splitValues(params.Sources),
+	}
+	if !at.IsZero() {
+		req.PlayHeadTimestampMs = at.UnixMilli()
+	}
+
+	events, total, err := t.listEvents(ctx, req, limit)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get events (STQL: %s): %w", query, err)
+	}
+
+	result := &EventsResult{Query: query, Total: total, Events: make([]Event, 0, len(events))}
+	for _, e := range events {
+		result.Events = append(result.Events, toEvent(e))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: formatEventsTimeline(events, total, query),
+			},
+		},
+	}, result, nil
+}
+
+// listEvents returns up to limit events of the request with the total number of matching events,
+// paging through the results using the cursor of the last received event
+func (t tool) listEvents(ctx context.Context, req *suseobservability.EventListRequest, limit int) ([]suseobservability.TopologyEvent, int64, error) {
+	var events []suseobservability.TopologyEvent
+	var total int64
+	for len(events) < limit {
+		req.Limit = min(eventsPageSize, limit-len(events))
+		res, err := t.client.GetEvents(ctx, req)
+		if err != nil {
+			return nil, 0, err
+		}
+		total = res.Total
+		events = append(events, res.Items...)
+		if len(res.Items) < req.Limit {
+			break
+		}
+		last := res.Items[len(res.Items)-1]
+		req.Cursor = &suseobservability.EventCursor{
+			LastEventTimestampMs: last.EventTime,
+			LastEventID:          last.Identifier,
+		}
+	}
+	return events, total, nil
+}
+
+// GetEvent retrieves a single event with its data, tags, source links and affected topology elements
+func (t tool) GetEvent(ctx context.Context, request *mcp.CallToolRequest, params GetEventParams) (*mcp.CallToolResult, *EventDetails, error) {
+	t, err := t.instance(params.Instance)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	start, end, err := parseTimeRange(params.Start, params.End, t.windows["getEvent"])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	event, err := t.client.GetEvent(ctx, params.EventID, start.UnixMilli(), end.UnixMilli())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get event: %w", err)
+	}
+
+	components, relations, err := event.DecodeElements()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decode event elements: %w", err)
+	}
+
+	details := &EventDetails{
+		Event:       toEvent(*event),
+		Description: event.Description,
+		Tags:        event.Tags,
+		Data:        event.Data,
+	}
+	if event.ProcessedTime > 0 {
+		details.ProcessedTime = time.UnixMilli(event.ProcessedTime)
+	}
+	for _, c := range components {
+		details.Components = append(details.Components, EventComponent{ID: c.ID, Name: c.Name, Type: c.TypeName, Identifiers: c.Identifiers})
+	}
+	for _, r := range relations {
+		details.Relations = append(details.Relations, EventRelation{ID: r.ID, Type: r.TypeName, Source: r.Source.ID, Target: r.Target.ID, Direction: string(r.DependencyDirection)})
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: formatEvent(event, components, relations),
+			},
+		},
+	}, details, nil
+}
+
+func toEvent(e suseobservability.TopologyEvent) Event {
+	return Event{
+		ID:                 e.Identifier,
+		Time:               time.UnixMilli(e.EventTime),
+		Category:           string(e.Category),
+		Type:               e.EventType,
+		Name:               e.Name,
+		Source:             e.Source,
+		ElementIdentifiers: e.ElementIdentifiers,
+		Links:              e.SourceLinks,
+	}
+}
+
+func formatEvent(e *suseobservability.TopologyEvent, components []suseobservability.EventComponent, relations []suseobservability.EventRelation) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Event '%s' (%s):\n\n", e.Name, e.Identifier))
+	sb.WriteString("| Field | Value |\n")
+	sb.WriteString("|---|---|\n")
+	sb.WriteString(fmt.Sprintf("| Category | %s |\n", e.Category))
+	sb.WriteString(fmt.Sprintf("| Type | %s |\n", e.EventType))
+	sb.WriteString(fmt.Sprintf("| Source | %s |\n", e.Source))
+	sb.WriteString(fmt.Sprintf("| Event Time | %s |\n", time.UnixMilli(e.EventTime).Format(time.RFC3339)))
+	sb.WriteString(fmt.Sprintf("| Processed Time | %s |\n", time.UnixMilli(e.ProcessedTime).Format(time.RFC3339)))
+	if e.Description != "" {
+		sb.WriteString(fmt.Sprintf("| Description | %s |\n", e.Description))
+	}
+
+	if len(e.Tags) > 0 {
+		sb.WriteString("\nTags:\n\n")
+		sb.WriteString("| Key | Value |\n")
+		sb.WriteString("|---|---|\n")
+		for _, tag := range e.Tags {
+			sb.WriteString(fmt.Sprintf("| %s | %s |\n", tag.Key, tag.Value))
+		}
+	}
+
+	if len(e.SourceLinks) > 0 {
+		sb.WriteString("\nSource links:\n\n")
+		for _, l := range e.SourceLinks {
+			sb.WriteString(fmt.Sprintf("- [%s](%s)\n", l.Title, l.URL))
+		}
+	}
+
+	if len(e.Data) > 0 {
+		keys := make([]string, 0, len(e.Data))
+		for k := range e.Data {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		sb.WriteString("\nData:\n\n")
+		sb.WriteString("| Key | Value |\n")
+		sb.WriteString("|---|---|\n")
+		for _, k := range keys {
+			value := fmt.Sprintf("%v", e.Data[k])
+			switch e.Data[k].(type) {
+			case map[string]interface{}, []interface{}:
+				if b, err := json.Marshal(e.Data[k]); err == nil {
+					value = fmt.Sprintf("`%s`", b)
+				}
+			}
+			sb.WriteString(fmt.Sprintf("| %s | %s |\n", k, value))
+		}
+	}
+
+	if len(components) > 0 {
+		sb.WriteString("\nAffected components:\n\n")
+		sb.WriteString("| Component Name | ID | Type | Identifiers |\n")
+		sb.WriteString("|---|---|---|---|\n")
+		for _, c := range components {
+			sb.WriteString(fmt.Sprintf("| %s | %d | %s | %s |\n", c.Name, c.ID, c.TypeName, strings.Join(c.Identifiers, ", ")))
+		}
+	}
+
+	if len(relations) > 0 {
+		sb.WriteString("\nAffected relations:\n\n")
+		sb.WriteString("| Source | Target | Type | Direction | ID |\n")
+		sb.WriteString("|---|---|---|---|---|\n")
+		for _, r := range relations {
+			sb.WriteString(fmt.Sprintf("| %s (%d) | %s (%d) | %s | %s | %d |\n",
+				r.Source.Name, r.Source.ID, r.Target.Name, r.Target.ID, r.TypeName, r.DependencyDirection, r.ID))
+		}
+	}
+
+	if len(components) == 0 && len(relations) == 0 && len(e.ElementIdentifiers) > 0 {
+		sb.WriteString(fmt.Sprintf("\nAffected element identifiers: %s\n", strings.Join(e.ElementIdentifiers, ", ")))
+	}
+
+	return sb.String()
+}
+
+func formatEventsTimeline(events []suseobservability.TopologyEvent, total int64, query string) string {
+	if len(events) == 0 {
+		return fmt.Sprintf("No events found for query: %s", query)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Found %d event(s), showing %d (STQL: %s):\n\n", total, len(events), query))
+	sb.WriteString("| Time | Category | Type | Name | Source | Elements | Event ID | Links |\n")
+	sb.WriteString("|---|---|---|---|---|---|---|---|\n")
+
+	for _, e := range events {
+		links := make([]string, 0, len(e.SourceLinks))
+		for _, l := range e.SourceLinks {
+			links = append(links, fmt.Sprintf("[%s](%s)", l.Title, l.URL))
+		}
+		linksCol := strings.Join(links, ", ")
+		if linksCol == "" {
+			linksCol = "-"
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %d | %s | %s |\n",
+			time.UnixMilli(e.EventTime).Format(time.RFC3339), e.Category, e.EventType, e.Name,
+			e.Source, len(e.ElementIdentifiers), e.Identifier, linksCol))
+	}
+
+	if total > int64(len(events)) {
+		sb.WriteString(fmt.Sprintf("\n%d more event(s) available, increase the limit or narrow the time window to see them.\n", total-int64(len(events))))
+	}
+
+	return sb.String()
+}
+
+func parseEventCategories(values string) ([]suseobservability.EventCategory, error) {
+	valid := []suseobservability.EventCategory{
+		suseobservability.EventCategoryChanges,
+		suseobservability.EventCategoryDeployments,
+		suseobservability.EventCategoryAlerts,
+		suseobservability.EventCategoryAnomalies,
+		suseobservability.EventCategoryActivities,
+		suseobservability.EventCategoryOthers,
+	}
+
+	var categories []suseobservability.EventCategory
+	for _, v := range splitValues(values) {
+		found := false
+		for _, c := range valid {
+			if strings.EqualFold(v, string(c)) {
+				categories = append(categories, c)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("invalid event category '%s'. Must be one of 'Changes', 'Deployments', 'Alerts', 'Anomalies', 'Activities', 'Others'", v)
+		}
+	}
+	return categories, nil
+}
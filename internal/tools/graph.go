@@ -0,0 +1,191 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"suse-observability-mcp/client/suseobservability"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type graphEdge struct {
+	RelationID int64
+	Source     int64
+	Target     int64
+	Type       string
+	// Direction is empty when unknown, the relation then being a dependency of the source on the target
+	Direction suseobservability.DependencyDirection
+}
+
+// dependencies returns the dependent and the dependency of every dependency the relation stands for:
+// the source depending on the target for a one-way relation, both ways for a two-way relation and
+// none for a relation that only connects the components.
+func (e graphEdge) dependencies() [][2]int64 {
+	direction := suseobservability.DependencyDirection(strings.ReplaceAll(strings.ToLower(string(e.Direction)), "_", "-"))
+	switch direction {
+	case suseobservability.DependencyDirectionNone:
+		return nil
+	case suseobservability.DependencyDirectionBoth:
+		return [][2]int64{{e.Source, e.Target}, {e.Target, e.Source}}
+	default:
+		return [][2]int64{{e.Source, e.Target}}
+	}
+}
+
+type componentGraph struct {
+	Nodes []suseobservability.ViewComponent
+	Edges []graphEdge
+}
+
+// getComponentsGraph runs the topology query and renders the components with the relations between them
+func (t tool) getComponentsGraph(ctx context.Context, params GetComponentsParams, query string, at time.Time) (*mcp.CallToolResult, *ComponentsResult, error) {
+	snapshot, err := t.client.SnapShotTopologyGraph(ctx, query, at)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query topology (STQL: %s): %w", query, err)
+	}
+
+	graph := buildComponentGraph(snapshot, t.nodeTypes)
+
+	var sb strings.Builder
+	sb.WriteString(formatComponentsTable(graph.Nodes, params, query, t.nodeTypes))
+	if len(graph.Nodes) > 0 {
+		sb.WriteString("\n")
+		sb.WriteString(formatAdjacencyList(graph))
+		switch params.Output {
+		case "mermaid":
+			sb.WriteString("\n")
+			sb.WriteString(formatMermaid(graph))
+		case "dot":
+			sb.WriteString("\n")
+			sb.WriteString(formatDOT(graph))
+		}
+	}
+
+	result := &ComponentsResult{
+		Query:      query,
+		At:         at,
+		Components: toComponents(graph.Nodes, t.nodeTypes),
+		Relations:  make([]Relation, 0, len(graph.Edges)),
+	}
+	for _, e := range graph.Edges {
+		r := Relation{ID: e.RelationID, Source: e.Source, Target: e.Target, Type: e.Type}
+		if r.Type == "-" {
+			r.Type = ""
+		}
+		result.Relations = append(result.Relations, r)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: sb.String(),
+			},
+		},
+	}, result, nil
+}
+
+// buildComponentGraph keeps the relations whose both ends are part of the snapshot.
+// When the snapshot carries no relations, edges are derived from the component relation IDs.
+func buildComponentGraph(snapshot *suseobservability.ViewSnapshotResponse, nodeTypes *nodeTypeResolver) componentGraph {
+	graph := componentGraph{Nodes: snapshot.Components}
+
+	inGraph := make(map[int64]bool, len(snapshot.Components))
+	for _, c := range snapshot.Components {
+		inGraph[c.ID] = true
+	}
+
+	if len(snapshot.Relations) > 0 {
+		for _, r := range snapshot.Relations {
+			if !inGraph[r.Source] || !inGraph[r.Target] {
+				continue
+			}
+			graph.Edges = append(graph.Edges, graphEdge{RelationID: r.ID, Source: r.Source, Target: r.Target, Type: nodeTypes.RelationType(r.Type), Direction: r.DependencyDirection})
+		}
+	} else {
+		sources := make(map[int64]int64)
+		for _, c := range snapshot.Components {
+			for _, id := range c.OutgoingRelations {
+				sources[id] = c.ID
+			}
+		}
+		for _, c := range snapshot.Components {
+			for _, id := range c.IncomingRelations {
+				if src, ok := sources[id]; ok {
+					graph.Edges = append(graph.Edges, graphEdge{RelationID: id, Source: src, Target: c.ID, Type: "-"})
+				}
+			}
+		}
+	}
+
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		if graph.Edges[i].Source != graph.Edges[j].Source {
+			return graph.Edges[i].Source < graph.Edges[j].Source
+		}
+		return graph.Edges[i].Target < graph.Edges[j].Target
+	})
+
+	return graph
+}
+
+func formatAdjacencyList(graph componentGraph) string {
+	names := make(map[int64]string, len(graph.Nodes))
+	for _, n := range graph.Nodes {
+		names[n.ID] = n.Name
+	}
+	outgoing := make(map[int64][]graphEdge)
+	for _, e := range graph.Edges {
+		outgoing[e.Source] = append(outgoing[e.Source], e)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Relations (%d):\n\n", len(graph.Edges)))
+	for _, n := range graph.Nodes {
+		edges := outgoing[n.ID]
+		if len(edges) == 0 {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("- %s (%d)\n", n.Name, n.ID))
+		for _, e := range edges {
+			sb.WriteString(fmt.Sprintf("  - %s -> %s (%d)\n", e.Type, names[e.Target], e.Target))
+		}
+	}
+	return sb.String()
+}
+
+func formatMermaid(graph componentGraph) string {
+	var sb strings.Builder
+	sb.WriteString("```mermaid\ngraph LR\n")
+	for _, n := range graph.Nodes {
+		sb.WriteString(fmt.Sprintf("  n%d[\"%s\"]\n", n.ID, strings.ReplaceAll(n.Name, "\"", "#quot;")))
+	}
+	for _, e := range graph.Edges {
+		if e.Type == "-" {
+			sb.WriteString(fmt.Sprintf("  n%d --> n%d\n", e.Source, e.Target))
+		} else {
+			sb.WriteString(fmt.Sprintf("  n%d -->|%s| n%d\n", e.Source, e.Type, e.Target))
+		}
+	}
+	sb.WriteString("```\n")
+	return sb.String()
+}
+
+func formatDOT(graph componentGraph) string {
+	var sb strings.Builder
+	sb.WriteString("```dot\ndigraph topology {\n")
+	for _, n := range graph.Nodes {
+		sb.WriteString(fmt.Sprintf("  \"%d\" [label=%q];\n", n.ID, n.Name))
+	}
+	for _, e := range graph.Edges {
+		if e.Type == "-" {
+			sb.WriteString(fmt.Sprintf("  \"%d\" -> \"%d\";\n", e.Source, e.Target))
+		} else {
+			sb.WriteString(fmt.Sprintf("  \"%d\" -> \"%d\" [label=%q];\n", e.Source, e.Target, e.Type))
+		}
+	}
+	sb.WriteString("}\n```\n")
+	return sb.String()
+}
@@ -0,0 +1,230 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type QueryInstancesParams struct {
+	Tool      string         `json:"tool" jsonschema:"The tool to run on every instance: 'getComponents' or 'getMetrics'"`
+	Arguments map[string]any `json:"arguments" jsonschema:"The arguments of the tool, the same as when calling it on a single instance (e.g. {\"names\": \"checkout\"} or {\"query\": \"up\", \"start\": \"1h\", \"end\": \"now\"})"`
+	Instances string         `json:"instances,omitempty" jsonschema:"Instances to query (comma-separated). Defaults to every instance."`
+}
+
+// InstanceStatus is the outcome of the query on one instance
+type InstanceStatus struct {
+	Instance string `json:"instance"`
+	Results  int    `json:"results"`
+	Error    string `json:"error,omitempty"`
+}
+
+type InstanceComponent struct {
+	Instance string `json:"instance"`
+	Component
+}
+
+type InstanceMetricSeries struct {
+	Instance string `json:"instance"`
+	MetricSeries
+}
+
+type InstancesResult struct {
+	Tool      string           `json:"tool"`
+	Instances []InstanceStatus `json:"instances"`
+	// Only set for getComponents
+	Query      string              `json:"query,omitempty"`
+	Components []InstanceComponent `json:"components,omitempty"`
+	// Only set for getMetrics
+	Series []InstanceMetricSeries `json:"series,omitempty"`
+}
+
+// instanceOutcome is the result of a tool on one instance
+type instanceOutcome[R any] struct {
+	instance string
+	text     string
+	result   R
+	err      error
+}
+
+// QueryInstances runs getComponents or getMetrics on several instances at once and merges the results
+func (t tool) QueryInstances(ctx context.Context, request *mcp.CallToolRequest, params QueryInstancesParams) (*mcp.CallToolResult, *InstancesResult, error) {
+	instances, err := t.selectInstances(params.Instances)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, ok := params.Arguments["instance"]; ok {
+		return nil, nil, errors.New("the arguments cannot set the instance, use instances instead")
+	}
+
+	result := &InstancesResult{Tool: params.Tool, Instances: make([]InstanceStatus, 0, len(instances))}
+	var output string
+	switch params.Tool {
+	case "getComponents":
+		var args GetComponentsParams
+		if err := decodeArguments(params.Arguments, &args); err != nil {
+			return nil, nil, err
+		}
+		if args.Output != "" && args.Output != "table" {
+			return nil, nil, fmt.Errorf("invalid output '%s'. Only the 'table' output can be merged, use getComponents with an instance for the graph outputs", args.Output)
+		}
+		outcomes := fanOut(instances, func(i tool) (*mcp.CallToolResult, *ComponentsResult, error) {
+			return i.GetComponents(ctx, request, args)
+		})
+		for _, o := range outcomes {
+			status := InstanceStatus{Instance: o.instance}
+			if o.err != nil {
+				status.Error = o.err.Error()
+			} else {
+				result.Query = o.result.Query
+				status.Results = len(o.result.Components)
+				for _, c := range o.result.Components {
+					result.Components = append(result.Components, InstanceComponent{Instance: o.instance, Component: c})
+				}
+			}
+			result.Instances = append(result.Instances, status)
+		}
+		// Sort by name so the same component of every instance is listed together
+		sort.SliceStable(result.Components, func(i, j int) bool {
+			return result.Components[i].Name < result.Components[j].Name
+		})
+		output = formatInstanceComponents(result)
+
+	case "getMetrics":
+		var args QueryMetricParams
+		if err := decodeArguments(params.Arguments, &args); err != nil {
+			return nil, nil, err
+		}
+		outcomes := fanOut(instances, func(i tool) (*mcp.CallToolResult, *MetricRangeResult, error) {
+			return i.QueryMetric(ctx, request, args)
+		})
+		var sb strings.Builder
+		for _, o := range outcomes {
+			status := InstanceStatus{Instance: o.instance}
+			sb.WriteString(fmt.Sprintf("## Instance %s\n\n", o.instance))
+			if o.err != nil {
+				status.Error = o.err.Error()
+				sb.WriteString(fmt.Sprintf("Failed: %s\n\n", o.err))
+			} else {
+				status.Results = len(o.result.Series)
+				for _, s := range o.result.Series {
+					result.Series = append(result.Series, InstanceMetricSeries{Instance: o.instance, MetricSeries: s})
+				}
+				sb.WriteString(o.text + "\n\n")
+			}
+			result.Instances = append(result.Instances, status)
+		}
+		output = sb.String()
+
+	default:
+		return nil, nil, fmt.Errorf("invalid tool '%s'. Must be 'getComponents' or 'getMetrics'", params.Tool)
+	}
+
+	var errs []error
+	for _, s := range result.Instances {
+		if s.Error != "" {
+			errs = append(errs, fmt.Errorf("%s: %s", s.Instance, s.Error))
+		}
+	}
+	if len(errs) == len(result.Instances) {
+		return nil, nil, fmt.Errorf("failed to run %s on every instance: %w", params.Tool, errors.Join(errs...))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: output,
+			},
+		},
+	}, result, nil
+}
+
+// selectInstances returns the tools of the named instances, or of every instance when no instance is named
+func (t tool) selectInstances(names string) ([]tool, error) {
+	if names == "" {
+		instances := make([]tool, 0, len(t.instances))
+		for _, i := range t.instances {
+			instances = append(instances, *i)
+		}
+		return instances, nil
+	}
+
+	var instances []tool
+	var seen []string
+	for _, name := range splitValues(names) {
+		if slices.Contains(seen, name) {
+			continue
+		}
+		i, err := t.instance(name)
+		if err != nil {
+			return nil, err
+		}
+		seen = append(seen, name)
+		instances = append(instances, i)
+	}
+	return instances, nil
+}
+
+// decodeArguments decodes the arguments of the tool run on every instance, rejecting unknown arguments
+func decodeArguments(arguments map[string]any, dest any) error {
+	data, err := json.Marshal(arguments)
+	if err != nil {
+		return fmt.Errorf("failed to encode arguments: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(dest); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	return nil
+}
+
+// fanOut runs the tool on every instance concurrently, returning the outcomes in the order of the instances
+func fanOut[R any](instances []tool, run func(tool) (*mcp.CallToolResult, R, error)) []instanceOutcome[R] {
+	outcomes := make([]instanceOutcome[R], len(instances))
+	var wg sync.WaitGroup
+	for n, i := range instances {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res, result, err := run(i)
+			outcomes[n] = instanceOutcome[R]{instance: i.instanceName, result: result, err: err}
+			if err == nil && len(res.Content) > 0 {
+				if text, ok := res.Content[0].(*mcp.TextContent); ok {
+					outcomes[n].text = text.Text
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return outcomes
+}
+
+func formatInstanceComponents(result *InstancesResult) string {
+	var sb strings.Builder
+	if len(result.Components) == 0 {
+		sb.WriteString(fmt.Sprintf("No components found on any instance for query: %s\n", result.Query))
+	} else {
+		sb.WriteString(fmt.Sprintf("Found %d component(s) on %d instance(s) for query: %s\n\n", len(result.Components), len(result.Instances), result.Query))
+		sb.WriteString("| Instance | Component Name | ID | Type | Layer | Domain | State |\n")
+		sb.WriteString("|---|---|---|---|---|---|---|\n")
+		for _, c := range result.Components {
+			sb.WriteString(fmt.Sprintf("| %s | %s | %d | %s | %s | %s | %s |\n", c.Instance, c.Name, c.ID, c.Type, c.Layer, c.Domain, c.State))
+		}
+	}
+
+	for _, s := range result.Instances {
+		if s.Error != "" {
+			sb.WriteString(fmt.Sprintf("\nFailed on instance %s: %s\n", s.Instance, s.Error))
+		}
+	}
+	return sb.String()
+}
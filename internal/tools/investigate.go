@@ -0,0 +1,415 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"suse-observability-mcp/client/suseobservability"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// maxMonitorLookups bounds the number of components whose monitors are retrieved
+	maxMonitorLookups      = 30
+	defaultCandidatesLimit = 10
+)
+
+type InvestigateComponentParams struct {
+	InstanceParams
+
+	ComponentID int64  `json:"component_id" jsonschema:"required,The ID of the component to investigate (from getComponents results)"`
+	Levels      string `json:"levels,omitempty" jsonschema:"Number of dependency levels (1-14) or 'all' to walk,default=3"`
+	Direction   string `json:"direction,omitempty" jsonschema:"Walk 'down' (the dependencies of the component), 'up' (the components depending on it) or 'both',default=both"`
+	Start       string `json:"start,omitempty" jsonschema:"Start of the window to look for changes in: 'now', duration ago (e.g. '2h', '1d'), 'now-2h', 'today', RFC3339 or Unix timestamp,default=2h"`
+	End         string `json:"end,omitempty" jsonschema:"End of the window to look for changes in: 'now', duration ago, RFC3339 or Unix timestamp,default=now"`
+	Limit       int    `json:"limit,omitempty" jsonschema:"Maximum number of likely causes to return,default=10"`
+}
+
+type RootCauseCandidate struct {
+	Component       Component          `json:"component"`
+	Relation        string             `json:"relation" jsonschema:"'target', 'dependency' (the target depends on it), 'dependent' (it depends on the target) or 'neighbor' (only connected by relations that are not dependencies)"`
+	Distance        int                `json:"distance" jsonschema:"Number of relations between the component and the target"`
+	FailingMonitors []ComponentMonitor `json:"failing_monitors,omitempty"`
+	Changes         []Event            `json:"changes,omitempty"`
+	Score           float64            `json:"score"`
+	Reasons         []string           `json:"reasons"`
+}
+
+type InvestigationResult struct {
+	Target     Component `json:"target"`
+	Query      string    `json:"query"`
+	Start      time.Time `json:"start"`
+	End        time.Time `json:"end"`
+	Components int       `json:"components"`
+	Unhealthy  int       `json:"unhealthy"`
+	// Changes is the number of change events read, ChangesTotal the number of change events in the window
+	Changes      int                  `json:"changes"`
+	ChangesTotal int64                `json:"changes_total"`
+	Total        int                  `json:"total"`
+	Candidates   []RootCauseCandidate `json:"candidates"`
+}
+
+// investigatedComponent gathers what is known of a component of the walked topology
+type investigatedComponent struct {
+	component suseobservability.ViewComponent
+	relation  string
+	distance  int
+	monitors  []ComponentMonitor
+	// monitorsErr is set when the monitors of the component could not be read
+	monitorsErr error
+	changes     []suseobservability.TopologyEvent
+	// unhealthyDependencies is set when one of the dependencies of the component is unhealthy as well
+	unhealthyDependencies bool
+}
+
+// InvestigateComponent walks the dependencies of a component and ranks the likely root causes of its problems
+func (t tool) InvestigateComponent(ctx context.Context, request *mcp.CallToolRequest, params InvestigateComponentParams) (*mcp.CallToolResult, *InvestigationResult, error) {
+	t, err := t.instance(params.Instance)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	levels := params.Levels
+	if levels == "" {
+		levels = "3"
+	}
+	direction := params.Direction
+	if direction == "" {
+		direction = "both"
+	}
+	if direction != "up" && direction != "down" && direction != "both" {
+		return nil, nil, fmt.Errorf("invalid direction '%s'. Must be 'up', 'down', or 'both'", direction)
+	}
+	limit := params.Limit
+	if limit <= 0 {
+		limit = defaultCandidatesLimit
+	}
+
+	start, end, err := parseTimeRange(params.Start, params.End, t.windows["investigateComponent"])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 1. Walk the topology around the component
+	query := fmt.Sprintf("id = %d OR withNeighborsOf(components = (id = %d), levels = \"%s\", direction = \"%s\")",
+		params.ComponentID, params.ComponentID, levels, direction)
+	snapshot, err := t.client.SnapShotTopologyGraph(ctx, query, time.Time{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query topology (STQL: %s): %w", query, err)
+	}
+	graph := buildComponentGraph(snapshot, t.nodeTypes)
+
+	components := make(map[int64]*investigatedComponent, len(graph.Nodes))
+	for _, c := range graph.Nodes {
+		components[c.ID] = &investigatedComponent{component: c}
+	}
+	target, ok := components[params.ComponentID]
+	if !ok {
+		return nil, nil, fmt.Errorf("component %d not found", params.ComponentID)
+	}
+	classifyRelations(graph, params.ComponentID, components)
+
+	// 2. Failing monitors of the unhealthy components, the closest ones first
+	var unhealthy []*investigatedComponent
+	for _, c := range components {
+		if isUnhealthy(c.component.State.HealthState) {
+			unhealthy = append(unhealthy, c)
+		}
+	}
+	sort.Slice(unhealthy, func(i, j int) bool {
+		if unhealthy[i].distance != unhealthy[j].distance {
+			return unhealthy[i].distance < unhealthy[j].distance
+		}
+		return unhealthy[i].component.ID < unhealthy[j].component.ID
+	})
+	for i, c := range unhealthy {
+		if i >= maxMonitorLookups {
+			break
+		}
+		// A component that is gone or not readable does not stop the investigation of the others
+		res, err := t.client.GetComponent(ctx, c.component.ID)
+		if err != nil {
+			c.monitorsErr = err
+			continue
+		}
+		for _, checkState := range res.Node.SyncedCheckStates {
+			m := parseSyncedCheckState(checkState)
+			if isUnhealthy(m.Health) {
+				c.monitors = append(c.monitors, m)
+			}
+		}
+	}
+
+	// 3. Recent changes of the walked components
+	byIdentifier := make(map[string]*investigatedComponent)
+	for _, c := range components {
+		for _, id := range c.component.Identifiers {
+			byIdentifier[id] = c
+		}
+	}
+	events, eventsTotal, err := t.listEvents(ctx, &suseobservability.EventListRequest{
+		StartTimestampMs: start.UnixMilli(),
+		EndTimestampMs:   end.UnixMilli(),
+		TopologyQuery:    query,
+		EventCategories:  []suseobservability.EventCategory{suseobservability.EventCategoryChanges, suseobservability.EventCategoryDeployments},
+	}, maxEventsLimit)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get change events: %w", err)
+	}
+	for _, e := range events {
+		affected := make(map[int64]*investigatedComponent)
+		for _, id := range e.ElementIdentifiers {
+			if c, ok := byIdentifier[id]; ok {
+				affected[c.component.ID] = c
+			}
+		}
+		if elements, _, err := e.DecodeElements(); err == nil {
+			for _, el := range elements {
+				if c, ok := components[el.ID]; ok {
+					affected[el.ID] = c
+				}
+			}
+		}
+		for _, c := range affected {
+			c.changes = append(c.changes, e)
+		}
+	}
+
+	// 4. Rank the likely causes
+	for _, e := range graph.Edges {
+		for _, d := range e.dependencies() {
+			dependent, dependency := components[d[0]], components[d[1]]
+			if dependent != nil && dependency != nil && isUnhealthy(dependency.component.State.HealthState) {
+				dependent.unhealthyDependencies = true
+			}
+		}
+	}
+	var candidates []RootCauseCandidate
+	for _, c := range components {
+		if candidate, ok := rankCandidate(c, end, t.nodeTypes); ok {
+			candidates = append(candidates, candidate)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Score != candidates[j].Score {
+			return candidates[i].Score > candidates[j].Score
+		}
+		if candidates[i].Distance != candidates[j].Distance {
+			return candidates[i].Distance > candidates[j].Distance
+		}
+		return candidates[i].Component.ID < candidates[j].Component.ID
+	})
+	total := len(candidates)
+	if total > limit {
+		candidates = candidates[:limit]
+	}
+
+	result := &InvestigationResult{
+		Target:       toComponent(target.component, t.nodeTypes),
+		Query:        query,
+		Start:        start,
+		End:          end,
+		Components:   len(components),
+		Unhealthy:    len(unhealthy),
+		Changes:      len(events),
+		ChangesTotal: eventsTotal,
+		Total:        total,
+		Candidates:   candidates,
+	}
+	if result.Candidates == nil {
+		result.Candidates = []RootCauseCandidate{}
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: formatInvestigation(result, levels, direction),
+			},
+		},
+	}, result, nil
+}
+
+// classifyRelations sets the relation of every component to the target and its distance in relations.
+// Following the dependencies of the relations leads to the dependencies of the target, relations that are
+// not dependencies only lead to neighbors.
+func classifyRelations(graph componentGraph, targetID int64, components map[int64]*investigatedComponent) {
+	outgoing := make(map[int64][]int64)
+	incoming := make(map[int64][]int64)
+	related := make(map[int64][]int64)
+	for _, e := range graph.Edges {
+		dependencies := e.dependencies()
+		if len(dependencies) == 0 {
+			related[e.Source] = append(related[e.Source], e.Target)
+			related[e.Target] = append(related[e.Target], e.Source)
+		}
+		for _, d := range dependencies {
+			outgoing[d[0]] = append(outgoing[d[0]], d[1])
+			incoming[d[1]] = append(incoming[d[1]], d[0])
+		}
+	}
+
+	// bfs returns the distance of every component reachable from the target through the given adjacency
+	bfs := func(adjacency ...map[int64][]int64) map[int64]int {
+		dist := map[int64]int{targetID: 0}
+		queue := []int64{targetID}
+		for len(queue) > 0 {
+			id := queue[0]
+			queue = queue[1:]
+			for _, adj := range adjacency {
+				for _, next := range adj[id] {
+					if _, ok := dist[next]; !ok {
+						dist[next] = dist[id] + 1
+						queue = append(queue, next)
+					}
+				}
+			}
+		}
+		return dist
+	}
+	dependencies := bfs(outgoing)
+	dependents := bfs(incoming)
+	neighbors := bfs(outgoing, incoming, related)
+
+	for id, c := range components {
+		switch {
+		case id == targetID:
+			c.relation = "target"
+		case dependencies[id] > 0:
+			c.relation, c.distance = "dependency", dependencies[id]
+		case dependents[id] > 0:
+			c.relation, c.distance = "dependent", dependents[id]
+		default:
+			c.relation, c.distance = "neighbor", neighbors[id]
+		}
+	}
+}
+
+// rankCandidate scores how likely a component is to be the root cause. Unhealthy components and
+// recently changed ones are candidates; deeper dependencies, failing monitors and recent changes
+// raise the score while dependents, which are more likely to suffer from the problem, lower it.
+func rankCandidate(c *investigatedComponent, end time.Time, nodeTypes *nodeTypeResolver) (RootCauseCandidate, bool) {
+	health := c.component.State.HealthState
+	if !isUnhealthy(health) && len(c.changes) == 0 {
+		return RootCauseCandidate{}, false
+	}
+
+	candidate := RootCauseCandidate{
+		Component:       toComponent(c.component, nodeTypes),
+		Relation:        c.relation,
+		Distance:        c.distance,
+		FailingMonitors: c.monitors,
+		Reasons:         []string{},
+	}
+
+	switch health {
+	case "CRITICAL":
+		candidate.Score += 3
+		candidate.Reasons = append(candidate.Reasons, "critical")
+	case "DEVIATING":
+		candidate.Score += 2
+		candidate.Reasons = append(candidate.Reasons, "deviating")
+	}
+	if isUnhealthy(health) && !c.unhealthyDependencies {
+		candidate.Score += 2
+		candidate.Reasons = append(candidate.Reasons, "no unhealthy dependencies of its own")
+	}
+	if n := len(c.monitors); n > 0 {
+		candidate.Score += float64(min(n, 3))
+		candidate.Reasons = append(candidate.Reasons, fmt.Sprintf("%d failing monitor(s)", n))
+	}
+	if c.monitorsErr != nil {
+		candidate.Reasons = append(candidate.Reasons, fmt.Sprintf("monitors unavailable: %v", c.monitorsErr))
+	}
+	if len(c.changes) > 0 {
+		latest := c.changes[0].EventTime
+		for _, e := range c.changes {
+			latest = max(latest, e.EventTime)
+			candidate.Changes = append(candidate.Changes, toEvent(e))
+		}
+		candidate.Score += 2
+		candidate.Reasons = append(candidate.Reasons, fmt.Sprintf("%d recent change(s), latest %s before the end of the window",
+			len(c.changes), end.Sub(time.UnixMilli(latest)).Truncate(time.Minute)))
+	}
+
+	switch c.relation {
+	case "dependency":
+		candidate.Score += 0.5 * float64(c.distance)
+		candidate.Reasons = append(candidate.Reasons, fmt.Sprintf("dependency %d level(s) down", c.distance))
+	case "dependent":
+		candidate.Score--
+		candidate.Reasons = append(candidate.Reasons, fmt.Sprintf("depends on the target (%d level(s) up)", c.distance))
+	}
+
+	return candidate, true
+}
+
+func isUnhealthy(health string) bool {
+	return health == "CRITICAL" || health == "DEVIATING"
+}
+
+func formatInvestigation(res *InvestigationResult, levels, direction string) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Investigation of '%s' (ID: %d, type: %s, state: %s): walked %d component(s) (levels %s, direction %s), %d unhealthy, looking for changes from %s to %s.\n\n",
+		res.Target.Name, res.Target.ID, res.Target.Type, res.Target.State, res.Components, levels, direction, res.Unhealthy,
+		res.Start.Format(time.RFC3339), res.End.Format(time.RFC3339)))
+
+	if int64(res.Changes) < res.ChangesTotal {
+		sb.WriteString(fmt.Sprintf("Only %d of the %d change events of the window were read, narrow the window to see the others.\n\n", res.Changes, res.ChangesTotal))
+	}
+
+	if len(res.Candidates) == 0 {
+		sb.WriteString("No unhealthy or recently changed components found around the target.\n")
+		return sb.String()
+	}
+
+	sb.WriteString("Likely root causes")
+	if len(res.Candidates) < res.Total {
+		sb.WriteString(fmt.Sprintf(" (showing %d of %d)", len(res.Candidates), res.Total))
+	}
+	sb.WriteString(":\n\n")
+	sb.WriteString("| Rank | Component Name | ID | Type | Relation | State | Failing Monitors | Changes | Score | Reasons |\n")
+	sb.WriteString("|---|---|---|---|---|---|---|---|---|---|\n")
+	for i, c := range res.Candidates {
+		relation := c.Relation
+		if c.Distance > 0 {
+			relation = fmt.Sprintf("%s (%d)", c.Relation, c.Distance)
+		}
+		state := c.Component.State
+		if state == "" {
+			state = "-"
+		}
+		sb.WriteString(fmt.Sprintf("| %d | %s | %d | %s | %s | %s | %d | %d | %.1f | %s |\n", i+1,
+			c.Component.Name, c.Component.ID, c.Component.Type, relation, state,
+			len(c.FailingMonitors), len(c.Changes), c.Score, strings.Join(c.Reasons, ", ")))
+	}
+
+	var monitors, changes strings.Builder
+	for _, c := range res.Candidates {
+		for _, m := range c.FailingMonitors {
+			monitors.WriteString(fmt.Sprintf("| %s | %s | %s |\n", c.Component.Name, m.Name, m.Health))
+		}
+		for _, e := range c.Changes {
+			changes.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n",
+				e.Time.Format(time.RFC3339), c.Component.Name, e.Name, e.Type, e.ID))
+		}
+	}
+	if monitors.Len() > 0 {
+		sb.WriteString("\nFailing monitors:\n\n")
+		sb.WriteString("| Component Name | Monitor Name | Health |\n")
+		sb.WriteString("|---|---|---|\n")
+		sb.WriteString(monitors.String())
+	}
+	if changes.Len() > 0 {
+		sb.WriteString("\nRecent changes:\n\n")
+		sb.WriteString("| Time | Component Name | Event | Type | Event ID |\n")
+		sb.WriteString("|---|---|---|---|---|\n")
+		sb.WriteString(changes.String())
+	}
+
+	sb.WriteString("\nUse getEvent, listMonitors and getMetrics on the top candidates to confirm the cause.\n")
+	return sb.String()
+}
@@ -0,0 +1,43 @@
+package tools
+
+import (
+	"testing"
+
+	"suse-observability-mcp/client/suseobservability"
+)
+
+func TestClassifyRelations(t *testing.T) {
+	// 1 is the target: it depends on 2 (one-way), 3 depends on it, it is connected to 4 (none),
+	// depends on and is depended on by 5 (both), and 4 depends on 6 (unknown direction)
+	graph := componentGraph{Edges: []graphEdge{
+		{Source: 1, Target: 2, Direction: suseobservability.DependencyDirectionOneWay},
+		{Source: 3, Target: 1, Direction: "ONE_WAY"},
+		{Source: 1, Target: 4, Direction: "NONE"},
+		{Source: 5, Target: 1, Direction: suseobservability.DependencyDirectionBoth},
+		{Source: 4, Target: 6},
+	}}
+	components := make(map[int64]*investigatedComponent)
+	for id := int64(1); id <= 6; id++ {
+		components[id] = &investigatedComponent{component: suseobservability.ViewComponent{ID: id}}
+	}
+
+	classifyRelations(graph, 1, components)
+
+	tests := []struct {
+		id       int64
+		relation string
+		distance int
+	}{
+		{id: 1, relation: "target"},
+		{id: 2, relation: "dependency", distance: 1},
+		{id: 3, relation: "dependent", distance: 1},
+		{id: 4, relation: "neighbor", distance: 1},
+		{id: 5, relation: "dependency", distance: 1},
+		{id: 6, relation: "neighbor", distance: 2},
+	}
+	for _, tt := range tests {
+		if c := components[tt.id]; c.relation != tt.relation || c.distance != tt.distance {
+			t.Errorf("component %d: relation = %s (%d), want %s (%d)", tt.id, c.relation, c.distance, tt.relation, tt.distance)
+		}
+	}
+}
@@ -3,6 +3,8 @@ package tools
 import (
 	"context"
 	"fmt"
+	"math"
+	"regexp"
 	"sort"
 	"strings"
 	"time"
@@ -12,28 +14,170 @@ import (
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
+const (
+	// Points per series when listMetrics evaluates the bound queries
+	boundMetricsMaxPoints = 60
+	// Series summarized per bound query, the highest peaks first
+	maxBoundSeries = 10
+	sparklineWidth = 20
+)
+
 type QueryMetricParams struct {
+	InstanceParams
+
 	Query string `json:"query" jsonschema:"The PromQL query to execute"`
-	Start string `json:"start" jsonschema:"Start time: 'now' or duration (e.g. '1h')"`
-	End   string `json:"end" jsonschema:"End time: 'now' or duration (e.g. '1h')"`
-	Step  string `json:"step" jsonschema:"Query resolution step width in duration format or float number of seconds"`
+	Start string `json:"start" jsonschema:"Start time: 'now', duration ago (e.g. '1h', '7d'), 'now-2h', 'today', 'yesterday', RFC3339 or Unix timestamp"`
+	End   string `json:"end" jsonschema:"End time: 'now', duration ago (e.g. '1h', '7d'), 'now-2h', 'today', 'yesterday', RFC3339 or Unix timestamp"`
+	Step  string `json:"step,omitempty" jsonschema:"Query resolution step width in duration format or float number of seconds. Chosen from the time range when empty: fine enough for about 1500 points in summary output, to fit max_points in raw output."`
+
+	Output    string `json:"output,omitempty" jsonschema:"Output format: 'summary' (statistics per series) or 'raw' (every point of every series),default=summary"`
+	MaxPoints int    `json:"max_points,omitempty" jsonschema:"Maximum number of points per series, at least 3. Series with more points are downsampled in raw output,default=120"`
+}
+
+type GetMetricValueParams struct {
+	InstanceParams
+
+	Query string `json:"query" jsonschema:"The PromQL query to execute"`
+	Time  string `json:"time,omitempty" jsonschema:"Evaluation time: 'now', duration ago (e.g. '1h'), 'now-2h', 'today', RFC3339 or Unix timestamp,default=now"`
+	Limit int    `json:"limit,omitempty" jsonschema:"Only return the series with the highest values, up to this number (0 returns all series)"`
+}
+
+type SearchMetricNamesParams struct {
+	InstanceParams
+
+	Search string `json:"search,omitempty" jsonschema:"Text contained in the metric names (case-insensitive), or a regular expression when regex is set"`
+	Regex  bool   `json:"regex,omitempty" jsonschema:"Interpret search as a regular expression"`
+	Start  string `json:"start,omitempty" jsonschema:"Start time: 'now', duration ago (e.g. '1h', '7d'), 'now-2h', 'today', 'yesterday', RFC3339 or Unix timestamp,default=1h"`
+	End    string `json:"end,omitempty" jsonschema:"End time: 'now', duration ago (e.g. '1h', '7d'), 'now-2h', 'today', 'yesterday', RFC3339 or Unix timestamp,default=now"`
+	Limit  int    `json:"limit,omitempty" jsonschema:"Maximum number of metric names to return,default=100"`
+}
+
+type GetMetricLabelsParams struct {
+	InstanceParams
+
+	Metric    string `json:"metric" jsonschema:"The metric name or a series selector (e.g. 'container_memory_usage' or 'up{namespace=\"prod\"}')"`
+	Label     string `json:"label,omitempty" jsonschema:"Only list the values of this label"`
+	Start     string `json:"start,omitempty" jsonschema:"Start time: 'now', duration ago (e.g. '1h', '7d'), 'now-2h', 'today', 'yesterday', RFC3339 or Unix timestamp,default=1h"`
+	End       string `json:"end,omitempty" jsonschema:"End time: 'now', duration ago (e.g. '1h', '7d'), 'now-2h', 'today', 'yesterday', RFC3339 or Unix timestamp,default=now"`
+	MaxValues int    `json:"max_values,omitempty" jsonschema:"Maximum number of values to show per label,default=20"`
 }
 
 type ListMetricsParams struct {
-	ComponentID int64 `json:"component_id" jsonschema:"required,The ID of the component to list bound metrics for"`
+	InstanceParams
+
+	ComponentID int64  `json:"component_id" jsonschema:"required,The ID of the component to list bound metrics for"`
+	WithData    bool   `json:"with_data,omitempty" jsonschema:"Evaluate every bound query over the window and summarize its series"`
+	Start       string `json:"start,omitempty" jsonschema:"Start time of the window: 'now', duration ago (e.g. '1h', '7d'), 'now-2h', 'today', 'yesterday', RFC3339 or Unix timestamp,default=1h"`
+	End         string `json:"end,omitempty" jsonschema:"End time of the window: 'now', duration ago (e.g. '1h', '7d'), 'now-2h', 'today', 'yesterday', RFC3339 or Unix timestamp,default=now"`
+}
+
+// BoundSeriesSummary is the compact summary of a series of an evaluated bound query
+type BoundSeriesSummary struct {
+	Labels    map[string]string `json:"labels,omitempty"`
+	Current   float64           `json:"current"`
+	Average   float64           `json:"average"`
+	Peak      float64           `json:"peak"`
+	PeakAt    int64             `json:"peak_at"`
+	Sparkline string            `json:"sparkline"`
+}
+
+// BoundQueryData is the outcome of evaluating a bound query for the component
+type BoundQueryData struct {
+	Query       string               `json:"query" jsonschema:"The query with the component variables substituted"`
+	Alias       string               `json:"alias,omitempty"`
+	TotalSeries int                  `json:"total_series"`
+	Series      []BoundSeriesSummary `json:"series,omitempty"`
+	Error       string               `json:"error,omitempty"`
+}
+
+type BoundMetricInfo struct {
+	Name    string           `json:"name"`
+	Unit    string           `json:"unit,omitempty"`
+	Queries []string         `json:"queries"`
+	Data    []BoundQueryData `json:"data,omitempty"`
+}
+
+type BoundMetricsResult struct {
+	ComponentID int64             `json:"component_id"`
+	Start       time.Time         `json:"start,omitzero"`
+	End         time.Time         `json:"end,omitzero"`
+	Step        string            `json:"step,omitempty"`
+	Metrics     []BoundMetricInfo `json:"metrics"`
+}
+
+// MetricSeries carries either the summary or the points of a series, depending on the output
+type MetricSeries struct {
+	Labels  map[string]string               `json:"labels,omitempty"`
+	Summary *SeriesSummary                  `json:"summary,omitempty"`
+	Points  []suseobservability.MetricPoint `json:"points,omitempty"`
+}
+
+type MetricRangeResult struct {
+	Query      string    `json:"query"`
+	Start      time.Time `json:"start"`
+	End        time.Time `json:"end"`
+	Step       string    `json:"step"`
+	Resolution string    `json:"resolution"`
+	// Number of series reduced to max_points with LTTB in raw output
+	Downsampled int            `json:"downsampled_series,omitempty"`
+	Series      []MetricSeries `json:"series"`
+}
+
+type MetricValue struct {
+	Labels    map[string]string `json:"labels,omitempty"`
+	Timestamp int64             `json:"timestamp"`
+	Value     float64           `json:"value"`
+}
+
+type MetricValueResult struct {
+	Query      string        `json:"query"`
+	ResultType string        `json:"result_type,omitempty"`
+	Total      int           `json:"total"`
+	Values     []MetricValue `json:"values"`
+}
+
+type MetricNamesResult struct {
+	Total int      `json:"total"`
+	Names []string `json:"names"`
+}
+
+type MetricLabel struct {
+	Name           string   `json:"name"`
+	DistinctValues int      `json:"distinct_values"`
+	Values         []string `json:"values"`
+}
+
+type MetricLabelsResult struct {
+	Metric string        `json:"metric"`
+	Labels []MetricLabel `json:"labels"`
 }
 
-// ListMetrics lists bound metrics for a specific component
-func (t tool) ListMetrics(ctx context.Context, request *mcp.CallToolRequest, params ListMetricsParams) (*mcp.CallToolResult, any, error) {
-	// Default time range: last 1 hour
-	end := time.Now()
-	start := end.Add(-1 * time.Hour)
+// ListMetrics lists bound metrics for a specific component, optionally with a summary of their data
+func (t tool) ListMetrics(ctx context.Context, request *mcp.CallToolRequest, params ListMetricsParams) (*mcp.CallToolResult, *BoundMetricsResult, error) {
+	t, err := t.instance(params.Instance)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	start, end, err := parseTimeRange(params.Start, params.End, t.windows["listMetrics"])
+	if err != nil {
+		return nil, nil, err
+	}
 
 	boundMetrics, err := t.client.GetBoundMetricsWithData(ctx, params.ComponentID, start, end)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to list bound metrics: %w", err)
 	}
 
+	result := &BoundMetricsResult{ComponentID: params.ComponentID, Metrics: make([]BoundMetricInfo, 0, len(boundMetrics.BoundMetrics))}
+	for _, bm := range boundMetrics.BoundMetrics {
+		info := BoundMetricInfo{Name: bm.Name, Unit: bm.Unit, Queries: make([]string, 0, len(bm.BoundQueries))}
+		for _, bq := range bm.BoundQueries {
+			info.Queries = append(info.Queries, bq.Expression)
+		}
+		result.Metrics = append(result.Metrics, info)
+	}
+
 	if len(boundMetrics.BoundMetrics) == 0 {
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{
@@ -41,7 +185,20 @@ func (t tool) ListMetrics(ctx context.Context, request *mcp.CallToolRequest, par
 					Text: fmt.Sprintf("No bound metrics found for component ID %d.", params.ComponentID),
 				},
 			},
-		}, nil, nil
+		}, result, nil
+	}
+
+	if params.WithData {
+		if err := t.evaluateBoundMetrics(ctx, result, boundMetrics.BoundMetrics, start, end); err != nil {
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{
+					Text: formatBoundMetricsData(result),
+				},
+			},
+		}, result, nil
 	}
 
 	var sb strings.Builder
@@ -61,33 +218,225 @@ func (t tool) ListMetrics(ctx context.Context, request *mcp.CallToolRequest, par
 				Text: sb.String(),
 			},
 		},
-	}, nil, nil
+	}, result, nil
+}
+
+// evaluateBoundMetrics runs every bound query of the component over the window and summarizes its series.
+// A query that cannot be expanded or fails is reported on its data entry, so the other metrics still show.
+func (t tool) evaluateBoundMetrics(ctx context.Context, result *BoundMetricsResult, boundMetrics []suseobservability.BoundMetric, start, end time.Time) error {
+	query := fmt.Sprintf("id = %d", result.ComponentID)
+	components, err := t.client.SnapShotTopologyQuery(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to query topology (STQL: %s): %w", query, err)
+	}
+	if len(components) == 0 {
+		return fmt.Errorf("component %d not found", result.ComponentID)
+	}
+	component := components[0]
+
+	step := autoStep(start, end, boundMetricsMaxPoints)
+	result.Start, result.End, result.Step = start, end, formatStep(step)
+
+	for i, bm := range boundMetrics {
+		info := &result.Metrics[i]
+		info.Data = make([]BoundQueryData, 0, len(bm.BoundQueries))
+		for _, bq := range bm.BoundQueries {
+			expanded, unresolved := expandQueryTemplate(bq.Expression, step, &component)
+			data := BoundQueryData{Query: expanded, Alias: bq.Alias}
+			if len(unresolved) > 0 {
+				data.Error = fmt.Sprintf("unresolved variables %s", strings.Join(unresolved, ", "))
+				info.Data = append(info.Data, data)
+				continue
+			}
+
+			res, err := t.client.QueryRangeMetric(ctx, expanded, start, end, formatStep(step), t.queryTimeout)
+			if err != nil {
+				data.Error = err.Error()
+				info.Data = append(info.Data, data)
+				continue
+			}
+
+			series := res.Data.Result
+			data.TotalSeries = len(series)
+			summaries := summarizeAll(series)
+			if len(summaries) > maxBoundSeries {
+				summaries = summaries[:maxBoundSeries]
+			}
+			points := make(map[string][]suseobservability.MetricPoint, len(series))
+			for _, s := range series {
+				points[seriesKey(s.Labels)] = s.Points
+			}
+			data.Series = make([]BoundSeriesSummary, 0, len(summaries))
+			for _, s := range summaries {
+				data.Series = append(data.Series, BoundSeriesSummary{
+					Labels:    s.Labels,
+					Current:   s.Last,
+					Average:   s.Mean,
+					Peak:      s.Max,
+					PeakAt:    s.MaxAt,
+					Sparkline: sparkline(points[seriesKey(s.Labels)], sparklineWidth),
+				})
+			}
+			info.Data = append(info.Data, data)
+		}
+	}
+	return nil
+}
+
+var templateVariable = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// expandQueryTemplate substitutes the variables of a bound query: the interval variables from the step, and
+// ${name}, ${tags.<key>} and ${properties.<key>} from the component when one is given. The rate interval covers
+// at least two minutes so that rates over sparse scrapes still get two samples. Variables that cannot be
+// resolved are left in place and returned.
+func expandQueryTemplate(expression string, step time.Duration, component *suseobservability.ViewComponent) (string, []string) {
+	var unresolved []string
+	expanded := templateVariable.ReplaceAllStringFunc(expression, func(match string) string {
+		name := templateVariable.FindStringSubmatch(match)[1]
+		switch {
+		case name == "__interval":
+			return formatStep(step)
+		case name == "__rate_interval":
+			return formatStep(max(2*step, 2*time.Minute))
+		case component == nil:
+		case name == "name":
+			return component.Name
+		case strings.HasPrefix(name, "tags."):
+			key := strings.TrimPrefix(name, "tags.")
+			for _, tag := range component.Tags {
+				if k, v, ok := strings.Cut(tag, ":"); ok && k == key {
+					return v
+				}
+			}
+		case strings.HasPrefix(name, "properties."):
+			if v, ok := component.Properties[strings.TrimPrefix(name, "properties.")]; ok {
+				return v
+			}
+		}
+		unresolved = append(unresolved, match)
+		return match
+	})
+	return expanded, unresolved
+}
+
+func formatBoundMetricsData(res *BoundMetricsResult) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Data of %d bound metrics for component ID %d from %s to %s (step %s):\n\n",
+		len(res.Metrics), res.ComponentID, res.Start.Format(time.RFC3339), res.End.Format(time.RFC3339), res.Step))
+	sb.WriteString("| Metric Name | Unit | Series | Current | Average | Peak | Peak At | Trend |\n")
+	sb.WriteString("|---|---|---|---|---|---|---|---|\n")
+
+	var failed []string
+	for _, m := range res.Metrics {
+		for _, d := range m.Data {
+			if d.Error != "" {
+				failed = append(failed, fmt.Sprintf("%s (`%s`): %s", m.Name, d.Query, d.Error))
+				continue
+			}
+			if len(d.Series) == 0 {
+				sb.WriteString(fmt.Sprintf("| %s | %s | - | - | - | - | - | no data |\n", m.Name, m.Unit))
+				continue
+			}
+			for _, s := range d.Series {
+				series := d.Alias
+				if len(s.Labels) > 0 {
+					labels := make([]string, 0, len(s.Labels))
+					for _, k := range sortedLabelKeys([]suseobservability.MetricResult{{Labels: s.Labels}}) {
+						labels = append(labels, fmt.Sprintf("%s=%s", k, s.Labels[k]))
+					}
+					series = strings.Join(labels, ", ")
+				}
+				if series == "" {
+					series = "-"
+				}
+				sb.WriteString(fmt.Sprintf("| %s | %s | %s | %.4f | %.4f | %.4f | %s | `%s` |\n",
+					m.Name, m.Unit, series, s.Current, s.Average, s.Peak, time.Unix(s.PeakAt, 0).Format(time.RFC3339), s.Sparkline))
+			}
+			if d.TotalSeries > len(d.Series) {
+				sb.WriteString(fmt.Sprintf("| %s | %s | %d more series | | | | | |\n", m.Name, m.Unit, d.TotalSeries-len(d.Series)))
+			}
+		}
+	}
+
+	if len(failed) > 0 {
+		sb.WriteString(fmt.Sprintf("\n%d bound query(ies) could not be evaluated:\n\n", len(failed)))
+		for _, f := range failed {
+			sb.WriteString(fmt.Sprintf("- %s\n", f))
+		}
+	}
+
+	return sb.String()
 }
 
 // QueryMetric queries a metric over a range of time
-func (t tool) QueryMetric(ctx context.Context, request *mcp.CallToolRequest, params QueryMetricParams) (*mcp.CallToolResult, any, error) {
-	start, err := parseTime(params.Start)
+func (t tool) QueryMetric(ctx context.Context, request *mcp.CallToolRequest, params QueryMetricParams) (*mcp.CallToolResult, *MetricRangeResult, error) {
+	t, err := t.instance(params.Instance)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to parse start time: %w", err)
+		return nil, nil, err
 	}
 
-	end, err := parseTime(params.End)
+	start, end, err := parseTimeRange(params.Start, params.End, t.windows["getMetrics"])
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to parse end time: %w", err)
+		return nil, nil, err
 	}
 
-	step := params.Step
-	if step == "" {
-		step = "1m"
+	maxPoints, err := parseMaxPoints(params.MaxPoints)
+	if err != nil {
+		return nil, nil, err
 	}
-	timeout := "30s"
 
-	result, err := t.client.QueryRangeMetric(ctx, params.Query, start, end, step, timeout)
+	// Pick the step from the time range unless the caller set one
+	var step time.Duration
+	resolution := "auto"
+	if params.Step == "" {
+		budget := summaryMaxPoints
+		if params.Output == "raw" {
+			budget = maxPoints
+		}
+		step = autoStep(start, end, budget)
+	} else {
+		step, err = parseStep(params.Step)
+		if err != nil {
+			return nil, nil, err
+		}
+		resolution = "requested"
+	}
+	result, err := t.client.QueryRangeMetric(ctx, params.Query, start, end, formatStep(step), t.queryTimeout)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to query range metri c: %w", err)
+		return nil, nil, fmt.Errorf("failed to query range metric: %w", err)
 	}
 
-	output := formatMetrics(result.Data.Result, params.Query)
+	structured := &MetricRangeResult{
+		Query:      params.Query,
+		Start:      start,
+		End:        end,
+		Step:       formatStep(step),
+		Resolution: resolution,
+		Series:     make([]MetricSeries, 0, len(result.Data.Result)),
+	}
+
+	var output string
+	switch params.Output {
+	case "", "summary":
+		summaries := summarizeAll(result.Data.Result)
+		output = formatMetricsSummary(summaries, sortedLabelKeys(result.Data.Result), params.Query, start, end, fmt.Sprintf("%s, %s", step, resolution))
+		for _, s := range summaries {
+			structured.Series = append(structured.Series, MetricSeries{Labels: s.Labels, Summary: &s})
+		}
+	case "raw":
+		series, downsampled := downsampleSeries(result.Data.Result, maxPoints)
+		output = fmt.Sprintf("Resolution: step %s (%s)", step, resolution)
+		if downsampled > 0 {
+			output += fmt.Sprintf(", %d series downsampled to %d points with LTTB", downsampled, maxPoints)
+		}
+		output += ".\n\n" + formatMetrics(series, params.Query)
+		structured.Downsampled = downsampled
+		for _, res := range series {
+			structured.Series = append(structured.Series, MetricSeries{Labels: res.Labels, Points: finitePoints(res.Points)})
+		}
+	default:
+		return nil, nil, fmt.Errorf("invalid output '%s'. Must be 'summary' or 'raw'", params.Output)
+	}
 
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{
@@ -95,15 +444,270 @@ func (t tool) QueryMetric(ctx context.Context, request *mcp.CallToolRequest, par
 				Text: output,
 			},
 		},
-	}, nil, nil
+	}, structured, nil
 }
 
-func formatMetrics(metricsResult []suseobservability.MetricResult, queryName string) string {
-	if len(metricsResult) == 0 {
+// SearchMetricNames searches the available metric names by substring or regular expression
+func (t tool) SearchMetricNames(ctx context.Context, request *mcp.CallToolRequest, params SearchMetricNamesParams) (*mcp.CallToolResult, *MetricNamesResult, error) {
+	t, err := t.instance(params.Instance)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	start, end, err := parseTimeRange(params.Start, params.End, t.windows["searchMetricNames"])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	match := func(name string) bool {
+		return strings.Contains(strings.ToLower(name), strings.ToLower(params.Search))
+	}
+	if params.Regex {
+		re, err := regexp.Compile(params.Search)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid regular expression: %w", err)
+		}
+		match = re.MatchString
+	}
+
+	limit := params.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	names, err := t.client.ListMetrics(ctx, start, end)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list metrics: %w", err)
+	}
+
+	var matched []string
+	for _, n := range names {
+		if match(n) {
+			matched = append(matched, n)
+		}
+	}
+	sort.Strings(matched)
+
+	if len(matched) == 0 {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{
+					Text: fmt.Sprintf("No metric names matching '%s' found out of %d metric(s).", params.Search, len(names)),
+				},
+			},
+		}, &MetricNamesResult{Names: []string{}}, nil
+	}
+
+	var sb strings.Builder
+	total := len(matched)
+	sb.WriteString(fmt.Sprintf("Found %d metric name(s) matching '%s'", total, params.Search))
+	if len(matched) > limit {
+		sb.WriteString(fmt.Sprintf(", showing the first %d", limit))
+		matched = matched[:limit]
+	}
+	sb.WriteString(":\n\n")
+	for _, n := range matched {
+		sb.WriteString(fmt.Sprintf("- %s\n", n))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: sb.String(),
+			},
+		},
+	}, &MetricNamesResult{Total: total, Names: matched}, nil
+}
+
+// GetMetricLabels lists the label names and values of a metric over a time window
+func (t tool) GetMetricLabels(ctx context.Context, request *mcp.CallToolRequest, params GetMetricLabelsParams) (*mcp.CallToolResult, *MetricLabelsResult, error) {
+	t, err := t.instance(params.Instance)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	start, end, err := parseTimeRange(params.Start, params.End, t.windows["getMetricLabels"])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	maxValues := params.MaxValues
+	if maxValues <= 0 {
+		maxValues = 20
+	}
+
+	labels := []string{params.Label}
+	if params.Label == "" {
+		labels, err = t.client.ListMetricLabels(ctx, params.Metric, start, end)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list labels of %s: %w", params.Metric, err)
+		}
+	}
+
+	result := &MetricLabelsResult{Metric: params.Metric, Labels: []MetricLabel{}}
+
+	var sb strings.Builder
+	for _, label := range labels {
+		if label == "__name__" {
+			continue
+		}
+		values, err := t.client.ListMetricLabelValues(ctx, label, params.Metric, start, end)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list values of label %s: %w", label, err)
+		}
+		if len(values) == 0 {
+			continue
+		}
+		sort.Strings(values)
+		shown := values
+		if len(shown) > maxValues {
+			shown = shown[:maxValues]
+		}
+		more := ""
+		if len(values) > len(shown) {
+			more = fmt.Sprintf(", ... (%d more)", len(values)-len(shown))
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %d | %s%s |\n", label, len(values), strings.Join(shown, ", "), more))
+		result.Labels = append(result.Labels, MetricLabel{Name: label, DistinctValues: len(values), Values: shown})
+	}
+
+	if len(result.Labels) == 0 {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{
+					Text: fmt.Sprintf("No labels found for %s. Check the metric name with searchMetricNames.", params.Metric),
+				},
+			},
+		}, result, nil
+	}
+
+	header := fmt.Sprintf("Found %d label(s) for %s:\n\n| Label | Distinct Values | Values |\n|---|---|---|\n", len(result.Labels), params.Metric)
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: header + sb.String(),
+			},
+		},
+	}, result, nil
+}
+
+// GetMetricValue evaluates an instant query at a single point in time
+func (t tool) GetMetricValue(ctx context.Context, request *mcp.CallToolRequest, params GetMetricValueParams) (*mcp.CallToolResult, *MetricValueResult, error) {
+	t, err := t.instance(params.Instance)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	timeParam := params.Time
+	if timeParam == "" {
+		timeParam = "now"
+	}
+	at, err := parseTime(timeParam)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse time: %w", err)
+	}
+
+	result, err := t.client.QueryMetric(ctx, params.Query, at, t.queryTimeout)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query metric: %w", err)
+	}
+
+	series, total := instantSeries(result.Data, params.Limit)
+	output := formatInstantMetrics(result.Data.ResultType, series, total, params.Query)
+
+	structured := &MetricValueResult{Query: params.Query, ResultType: result.Data.ResultType, Total: total, Values: make([]MetricValue, 0, len(series))}
+	for _, res := range series {
+		p := res.Points[0]
+		if math.IsNaN(p.Value) || math.IsInf(p.Value, 0) {
+			continue
+		}
+		structured.Values = append(structured.Values, MetricValue{Labels: res.Labels, Timestamp: p.Timestamp, Value: p.Value})
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: output,
+			},
+		},
+	}, structured, nil
+}
+
+// instantSeries returns the series with a value sorted from the highest value, up to limit, and the total number of them
+func instantSeries(data suseobservability.MetricData, limit int) ([]suseobservability.MetricResult, int) {
+	series := make([]suseobservability.MetricResult, 0, len(data.Result))
+	for _, res := range data.Result {
+		if len(res.Points) > 0 {
+			series = append(series, res)
+		}
+	}
+	sort.SliceStable(series, func(i, j int) bool {
+		return series[i].Points[0].Value > series[j].Points[0].Value
+	})
+	total := len(series)
+	if limit > 0 && limit < total {
+		series = series[:limit]
+	}
+	return series, total
+}
+
+func formatInstantMetrics(resultType string, series []suseobservability.MetricResult, total int, queryName string) string {
+	if len(series) == 0 {
 		return fmt.Sprintf("No data found for query: %s", queryName)
 	}
 
-	// Collect all unique label keys across all series
+	// Scalar and string results carry a single value without labels
+	if resultType == "scalar" || resultType == "string" {
+		p := series[0].Points[0]
+		return fmt.Sprintf("Result of %s (%s) at %s: %.4f", queryName, resultType,
+			time.Unix(p.Timestamp, 0).Format(time.RFC3339), p.Value)
+	}
+
+	sortedKeys := sortedLabelKeys(series)
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Found %d series", total))
+	if len(series) < total {
+		sb.WriteString(fmt.Sprintf(", showing the %d highest", len(series)))
+	}
+	if len(series) > 0 {
+		sb.WriteString(fmt.Sprintf(" at %s", time.Unix(series[0].Points[0].Timestamp, 0).Format(time.RFC3339)))
+	}
+	sb.WriteString(":\n\n")
+
+	// Header
+	sb.WriteString("| Value |")
+	for _, k := range sortedKeys {
+		sb.WriteString(fmt.Sprintf(" %s |", k))
+	}
+	sb.WriteString("\n")
+
+	// Separator
+	sb.WriteString("|---|")
+	for range sortedKeys {
+		sb.WriteString("---|")
+	}
+	sb.WriteString("\n")
+
+	// Data rows
+	for _, res := range series {
+		sb.WriteString(fmt.Sprintf("| %.4f |", res.Points[0].Value))
+		for _, k := range sortedKeys {
+			val := res.Labels[k]
+			if val == "" {
+				val = "-"
+			}
+			sb.WriteString(fmt.Sprintf(" %s |", val))
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// sortedLabelKeys collects all unique label keys across all series, sorted for a consistent column order
+func sortedLabelKeys(metricsResult []suseobservability.MetricResult) []string {
 	labelKeys := make(map[string]bool)
 	for _, res := range metricsResult {
 		for k := range res.Labels {
@@ -113,12 +717,105 @@ func formatMetrics(metricsResult []suseobservability.MetricResult, queryName str
 		}
 	}
 
-	// Convert to sorted slice for consistent column order
 	var sortedKeys []string
 	for k := range labelKeys {
 		sortedKeys = append(sortedKeys, k)
 	}
 	sort.Strings(sortedKeys)
+	return sortedKeys
+}
+
+// downsampleSeries reduces every series to at most maxPoints points, returning how many series were reduced
+func downsampleSeries(metricsResult []suseobservability.MetricResult, maxPoints int) ([]suseobservability.MetricResult, int) {
+	downsampled := 0
+	series := make([]suseobservability.MetricResult, 0, len(metricsResult))
+	for _, res := range metricsResult {
+		if n := len(res.Points); n > maxPoints {
+			res.Points = downsampleLTTB(res.Points, maxPoints)
+			// LTTB keeps every point below 3 points
+			if len(res.Points) < n {
+				downsampled++
+			}
+		}
+		series = append(series, res)
+	}
+	return series, downsampled
+}
+
+// summarizeAll summarizes every series, the series with the highest peaks first
+func summarizeAll(metricsResult []suseobservability.MetricResult) []SeriesSummary {
+	summaries := make([]SeriesSummary, 0, len(metricsResult))
+	for _, res := range metricsResult {
+		summaries = append(summaries, summarizeSeries(res))
+	}
+	sort.SliceStable(summaries, func(i, j int) bool {
+		return summaries[i].Max > summaries[j].Max
+	})
+	return summaries
+}
+
+// finitePoints drops the NaN and infinite values, which can't be encoded in JSON
+func finitePoints(points []suseobservability.MetricPoint) []suseobservability.MetricPoint {
+	out := make([]suseobservability.MetricPoint, 0, len(points))
+	for _, p := range points {
+		if !math.IsNaN(p.Value) && !math.IsInf(p.Value, 0) {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func formatMetricsSummary(summaries []SeriesSummary, sortedKeys []string, queryName string, start, end time.Time, step string) string {
+	if len(summaries) == 0 {
+		return fmt.Sprintf("No data found for query: %s", queryName)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Summary of %d series from %s to %s (step %s). Trend is the linear slope per hour. Use output 'raw' to get every point.\n\n",
+		len(summaries), start.Format(time.RFC3339), end.Format(time.RFC3339), step))
+
+	// Header
+	sb.WriteString("| Points | Min | Min At | Max | Max At | Mean | P50 | P95 | P99 | First | Last | Trend/h |")
+	for _, k := range sortedKeys {
+		sb.WriteString(fmt.Sprintf(" %s |", k))
+	}
+	sb.WriteString("\n")
+
+	// Separator
+	sb.WriteString("|---|---|---|---|---|---|---|---|---|---|---|---|")
+	for range sortedKeys {
+		sb.WriteString("---|")
+	}
+	sb.WriteString("\n")
+
+	// Data rows
+	for _, s := range summaries {
+		if s.Points == 0 {
+			sb.WriteString("| 0 | - | - | - | - | - | - | - | - | - | - | - |")
+		} else {
+			sb.WriteString(fmt.Sprintf("| %d | %.4f | %s | %.4f | %s | %.4f | %.4f | %.4f | %.4f | %.4f | %.4f | %+.4f |",
+				s.Points, s.Min, time.Unix(s.MinAt, 0).Format(time.RFC3339), s.Max, time.Unix(s.MaxAt, 0).Format(time.RFC3339),
+				s.Mean, s.P50, s.P95, s.P99, s.First, s.Last, s.Slope*3600))
+		}
+		for _, k := range sortedKeys {
+			val := s.Labels[k]
+			if val == "" {
+				val = "-"
+			}
+			sb.WriteString(fmt.Sprintf(" %s |", val))
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+func formatMetrics(metricsResult []suseobservability.MetricResult, queryName string) string {
+	if len(metricsResult) == 0 {
+		return fmt.Sprintf("No data found for query: %s", queryName)
+	}
+
+	sortedKeys := sortedLabelKeys(metricsResult)
 
 	var sb strings.Builder
 
@@ -155,13 +852,3 @@ func formatMetrics(metricsResult []suseobservability.MetricResult, queryName str
 
 	return sb.String()
 }
-
-func parseTime(s string) (time.Time, error) {
-	if s == "now" {
-		return time.Now(), nil
-	}
-	if d, err := time.ParseDuration(s); err == nil {
-		return time.Now().Add(-d), nil
-	}
-	return time.Time{}, fmt.Errorf("invalid time format: %s (expected 'now' or duration like '1h')", s)
-}
@@ -3,23 +3,141 @@ package tools
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
+	"time"
+
+	"suse-observability-mcp/client/suseobservability"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
 type ListMonitorsParams struct {
+	InstanceParams
+
 	ComponentID int64 `json:"component_id" jsonschema:"required,The ID of the component to list monitors for"`
 }
 
+type GetMonitorsOverviewParams struct {
+	InstanceParams
+
+	Tags          string `json:"tags,omitempty" jsonschema:"Monitor tags to match (comma-separated). A monitor matches when it has any of the tags."`
+	Status        string `json:"status,omitempty" jsonschema:"Monitor statuses to match (comma-separated): 'ENABLED', 'DISABLED'"`
+	RuntimeStatus string `json:"runtime_status,omitempty" jsonschema:"Monitor runtime statuses to match (comma-separated): 'ENABLED', 'DISABLED', 'ERROR', 'WARNING'"`
+}
+
+type GetMonitorCheckStatesParams struct {
+	InstanceParams
+
+	Monitor      string `json:"monitor" jsonschema:"required,The ID or URN of the monitor (from getMonitorsOverview or listMonitors)"`
+	HealthStates string `json:"healthstates,omitempty" jsonschema:"Health states to list (comma-separated, e.g., 'CRITICAL,DEVIATING'),default=CRITICAL,DEVIATING"`
+	Limit        int    `json:"limit,omitempty" jsonschema:"Maximum number of check states to return per health state,default=100"`
+}
+
+type GetMonitorCheckStatusParams struct {
+	InstanceParams
+
+	CheckStateID string `json:"check_state_id" jsonschema:"required,The check state ID (from getMonitorCheckStates results)"`
+	ComponentID  int64  `json:"component_id" jsonschema:"required,The element ID of the component the check state is on (from getMonitorCheckStates results)"`
+}
+
+type ComponentMonitor struct {
+	Name            string `json:"name"`
+	Health          string `json:"health"`
+	Query           string `json:"query,omitempty"`
+	RemediationHint string `json:"remediation_hint,omitempty"`
+}
+
+type ComponentMonitorsResult struct {
+	ComponentID   int64              `json:"component_id"`
+	ComponentName string             `json:"component_name"`
+	Monitors      []ComponentMonitor `json:"monitors"`
+}
+
+type MonitorError struct {
+	Level string `json:"level"`
+	Count int    `json:"count"`
+	Error string `json:"error"`
+}
+
+type MonitorOverview struct {
+	ID            int64          `json:"id"`
+	Name          string         `json:"name"`
+	Function      string         `json:"function"`
+	Status        string         `json:"status"`
+	RuntimeStatus string         `json:"runtime_status"`
+	Tags          []string       `json:"tags,omitempty"`
+	Critical      int            `json:"critical"`
+	Deviating     int            `json:"deviating"`
+	Clear         int            `json:"clear"`
+	Unknown       int            `json:"unknown"`
+	LastRun       time.Time      `json:"last_run,omitzero"`
+	LastFailedRun time.Time      `json:"last_failed_run,omitzero"`
+	Errors        []MonitorError `json:"errors,omitempty"`
+}
+
+type MonitorsOverviewResult struct {
+	Total    int               `json:"total"`
+	Monitors []MonitorOverview `json:"monitors"`
+}
+
+type CheckState struct {
+	CheckStateID  string `json:"check_state_id"`
+	ComponentName string `json:"component_name"`
+	ElementID     int64  `json:"element_id"`
+	Health        string `json:"health"`
+	Message       string `json:"message,omitempty"`
+}
+
+type MonitorCheckStatesResult struct {
+	Monitor      string       `json:"monitor"`
+	MonitorName  string       `json:"monitor_name"`
+	HealthStates []string     `json:"healthstates"`
+	CheckStates  []CheckState `json:"check_states"`
+}
+
+type CheckStatusQuery struct {
+	Alias string `json:"alias,omitempty"`
+	Query string `json:"query"`
+}
+
+type CheckStatusMetric struct {
+	Name    string             `json:"name"`
+	Unit    string             `json:"unit,omitempty"`
+	Queries []CheckStatusQuery `json:"queries"`
+}
+
+type CheckStatusResult struct {
+	CheckStateID         string              `json:"check_state_id"`
+	Health               string              `json:"health"`
+	Triggered            time.Time           `json:"triggered,omitzero"`
+	MonitorName          string              `json:"monitor_name"`
+	MonitorDescription   string              `json:"monitor_description,omitempty"`
+	ComponentID          int64               `json:"component_id"`
+	ComponentName        string              `json:"component_name"`
+	ComponentType        string              `json:"component_type"`
+	ComponentIdentifier  string              `json:"component_identifier"`
+	Message              string              `json:"message"`
+	Reason               string              `json:"reason,omitempty"`
+	TroubleshootingSteps string              `json:"troubleshooting_steps,omitempty"`
+	Metrics              []CheckStatusMetric `json:"metrics"`
+}
+
 // ListMonitors lists monitors for a specific component using the Component API
-func (t tool) ListMonitors(ctx context.Context, request *mcp.CallToolRequest, params ListMonitorsParams) (*mcp.CallToolResult, any, error) {
+func (t tool) ListMonitors(ctx context.Context, request *mcp.CallToolRequest, params ListMonitorsParams) (*mcp.CallToolResult, *ComponentMonitorsResult, error) {
+	t, err := t.instance(params.Instance)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	// Get component with synced check states
 	res, err := t.client.GetComponent(ctx, params.ComponentID)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get component: %w", err)
 	}
 
+	result := &ComponentMonitorsResult{ComponentID: params.ComponentID, ComponentName: res.Node.Name, Monitors: []ComponentMonitor{}}
+
 	// Check if component has synced check states
 	if len(res.Node.SyncedCheckStates) == 0 {
 		return &mcp.CallToolResult{
@@ -28,7 +146,7 @@ func (t tool) ListMonitors(ctx context.Context, request *mcp.CallToolRequest, pa
 					Text: fmt.Sprintf("No monitors found for component '%s' (ID: %d)", res.Node.Name, params.ComponentID),
 				},
 			},
-		}, nil, nil
+		}, result, nil
 	}
 
 	// Build output table
@@ -38,48 +156,303 @@ func (t tool) ListMonitors(ctx context.Context, request *mcp.CallToolRequest, pa
 	sb.WriteString("|---|---|---|---|\n")
 
 	for _, checkStateData := range res.Node.SyncedCheckStates {
-		// Extract monitor name from check state data
-		name := ""
-		if nameField, ok := checkStateData["name"].(string); ok {
-			name = nameField
+		monitor := parseSyncedCheckState(checkStateData)
+
+		query := "-"
+		if monitor.Query != "" {
+			query = fmt.Sprintf("`%s`", monitor.Query)
+			if len(query) > 80 {
+				query = query[:77] + "...`"
+			}
+		}
+		hint := "-"
+		if monitor.RemediationHint != "" {
+			hint = monitor.RemediationHint
+			if len(hint) > 100 {
+				hint = hint[:97] + "..."
+			}
+		}
+
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n", monitor.Name, monitor.Health, query, hint))
+		result.Monitors = append(result.Monitors, monitor)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: sb.String(),
+			},
+		},
+	}, result, nil
+}
+
+// GetMonitorsOverview lists every monitor with its function, runtime status, health state counts and errors
+func (t tool) GetMonitorsOverview(ctx context.Context, request *mcp.CallToolRequest, params GetMonitorsOverviewParams) (*mcp.CallToolResult, *MonitorsOverviewResult, error) {
+	t, err := t.instance(params.Instance)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	res, err := t.client.GetMonitorsOverview(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get monitors overview: %w", err)
+	}
+
+	tags := splitValues(params.Tags)
+	statuses := make(map[string]bool)
+	for _, s := range splitValues(params.Status) {
+		statuses[strings.ToUpper(s)] = true
+	}
+	runtimeStatuses := make(map[string]bool)
+	for _, s := range splitValues(params.RuntimeStatus) {
+		runtimeStatuses[strings.ToUpper(s)] = true
+	}
+
+	var monitors []suseobservability.MonitorOverview
+	for _, m := range res.Monitors {
+		if len(statuses) > 0 && !statuses[string(m.Monitor.Status)] {
+			continue
 		}
+		if len(runtimeStatuses) > 0 && !runtimeStatuses[string(m.Monitor.RuntimeStatus)] {
+			continue
+		}
+		if len(tags) > 0 && !hasAnyTag(m.Monitor.Tags, tags) {
+			continue
+		}
+		monitors = append(monitors, m)
+	}
 
-		// Extract health
-		health := ""
-		if healthField, ok := checkStateData["health"].(string); ok {
-			health = healthField
+	if len(monitors) == 0 {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{
+					Text: fmt.Sprintf("No monitors found out of %d monitor(s) matching the given filters.", len(res.Monitors)),
+				},
+			},
+		}, &MonitorsOverviewResult{Total: len(res.Monitors), Monitors: []MonitorOverview{}}, nil
+	}
+
+	// Broken monitors first, then the noisiest ones
+	sort.SliceStable(monitors, func(i, j int) bool {
+		ri, rj := runtimeStatusRank(monitors[i].Monitor.RuntimeStatus), runtimeStatusRank(monitors[j].Monitor.RuntimeStatus)
+		if ri != rj {
+			return ri < rj
 		}
+		ni := monitors[i].RuntimeMetrics.CriticalCount + monitors[i].RuntimeMetrics.DeviatingCount
+		nj := monitors[j].RuntimeMetrics.CriticalCount + monitors[j].RuntimeMetrics.DeviatingCount
+		return ni > nj
+	})
 
-		// Extract data.displayTimeSeries for queries
-		query := "-"
-		hint := "-"
-		if dataField, ok := checkStateData["data"].(map[string]interface{}); ok {
-			// Extract remediation hint
-			if remediationHint, ok := dataField["remediationHint"].(string); ok {
-				hint = remediationHint
-				if len(hint) > 100 {
-					hint = hint[:97] + "..."
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Found %d monitor(s) out of %d:\n\n", len(monitors), len(res.Monitors)))
+	sb.WriteString("| Monitor Name | ID | Function | Status | Runtime Status | Critical | Deviating | Clear | Unknown | Last Run | Last Failed Run | Errors |\n")
+	sb.WriteString("|---|---|---|---|---|---|---|---|---|---|---|---|\n")
+
+	result := &MonitorsOverviewResult{Total: len(res.Monitors), Monitors: make([]MonitorOverview, 0, len(monitors))}
+	withErrors := 0
+	for _, m := range monitors {
+		result.Monitors = append(result.Monitors, toMonitorOverview(m))
+		rm := m.RuntimeMetrics
+		sb.WriteString(fmt.Sprintf("| %s | %d | %s | %s | %s | %d | %d | %d | %d | %s | %s | %d |\n",
+			m.Monitor.Name, m.Monitor.Id, m.Function.Name, m.Monitor.Status, m.Monitor.RuntimeStatus,
+			rm.CriticalCount, rm.DeviatingCount, rm.ClearCount, rm.UnknownCount,
+			formatTimestampMs(rm.LastRunTimestamp), formatTimestampMs(rm.LastFailedRunTimestamp), len(m.Errors)))
+		if len(m.Errors) > 0 {
+			withErrors++
+		}
+	}
+
+	if withErrors > 0 {
+		sb.WriteString("\nRecent errors:\n\n")
+		sb.WriteString("| Monitor Name | Level | Count | Error |\n")
+		sb.WriteString("|---|---|---|---|\n")
+		for _, m := range monitors {
+			for _, e := range m.Errors {
+				msg := strings.ReplaceAll(e.Error, "\n", " ")
+				if len(msg) > 200 {
+					msg = msg[:197] + "..."
 				}
+				sb.WriteString(fmt.Sprintf("| %s | %s | %d | %s |\n", m.Monitor.Name, e.Level, e.Count, msg))
 			}
+		}
+	}
 
-			// Extract query from displayTimeSeries
-			if displayTimeSeries, ok := dataField["displayTimeSeries"].([]interface{}); ok && len(displayTimeSeries) > 0 {
-				if series, ok := displayTimeSeries[0].(map[string]interface{}); ok {
-					if queries, ok := series["queries"].([]interface{}); ok && len(queries) > 0 {
-						if queryData, ok := queries[0].(map[string]interface{}); ok {
-							if q, ok := queryData["query"].(string); ok {
-								query = fmt.Sprintf("`%s`", q)
-								if len(query) > 80 {
-									query = query[:77] + "...`"
-								}
-							}
-						}
-					}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: sb.String(),
+			},
+		},
+	}, result, nil
+}
+
+// GetMonitorCheckStates lists the components a monitor currently flags with the given health states
+func (t tool) GetMonitorCheckStates(ctx context.Context, request *mcp.CallToolRequest, params GetMonitorCheckStatesParams) (*mcp.CallToolResult, *MonitorCheckStatesResult, error) {
+	t, err := t.instance(params.Instance)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	healthStates := splitValues(params.HealthStates)
+	if len(healthStates) == 0 {
+		healthStates = []string{"CRITICAL", "DEVIATING"}
+	}
+	limit := params.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	monitorName := params.Monitor
+	if m, err := t.client.GetMonitor(ctx, params.Monitor); err == nil && m.Name != "" {
+		monitorName = m.Name
+	}
+
+	var states []suseobservability.ViewCheckState
+	for _, hs := range healthStates {
+		res, err := t.client.GetMonitorCheckStates(ctx, params.Monitor, strings.ToUpper(hs), limit, 0)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get check states for monitor %s: %w", params.Monitor, err)
+		}
+		states = append(states, res.States...)
+	}
+
+	result := &MonitorCheckStatesResult{Monitor: params.Monitor, MonitorName: monitorName, HealthStates: healthStates, CheckStates: make([]CheckState, 0, len(states))}
+	for _, st := range states {
+		result.CheckStates = append(result.CheckStates, CheckState{
+			CheckStateID:  st.CheckStateId,
+			ComponentName: st.Name,
+			ElementID:     st.TopologyElementId,
+			Health:        st.Health,
+			Message:       st.Message,
+		})
+	}
+
+	if len(states) == 0 {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{
+					Text: fmt.Sprintf("Monitor '%s' has no check states in %s.", monitorName, strings.Join(healthStates, ", ")),
+				},
+			},
+		}, result, nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Monitor '%s' flags %d component(s) (%s):\n\n", monitorName, len(states), strings.Join(healthStates, ", ")))
+	sb.WriteString("| Component | Element ID | Health | Check State ID | Message |\n")
+	sb.WriteString("|---|---|---|---|---|\n")
+
+	for _, st := range states {
+		msg := strings.ReplaceAll(st.Message, "\n", " ")
+		if len(msg) > 150 {
+			msg = msg[:147] + "..."
+		}
+		if msg == "" {
+			msg = "-"
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %d | %s | %s | %s |\n", st.Name, st.TopologyElementId, st.Health, st.CheckStateId, msg))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: sb.String(),
+			},
+		},
+	}, result, nil
+}
+
+// GetMonitorCheckStatus retrieves the details of a check state, including the metrics behind it
+func (t tool) GetMonitorCheckStatus(ctx context.Context, request *mcp.CallToolRequest, params GetMonitorCheckStatusParams) (*mcp.CallToolResult, *CheckStatusResult, error) {
+	t, err := t.instance(params.Instance)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// A check status has its own ID, found through the check state among the synced check states of the component
+	checkStateID := strings.TrimSpace(params.CheckStateID)
+	component, err := t.client.GetComponent(ctx, params.ComponentID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get component %d: %w", params.ComponentID, err)
+	}
+	id, ok := checkStatusID(component.Node.SyncedCheckStates, checkStateID)
+	if !ok {
+		return nil, nil, fmt.Errorf("component %d has no check state '%s'", params.ComponentID, checkStateID)
+	}
+
+	status, err := t.client.GetMonitorCheckStatus(ctx, id, 0)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get check status: %w", err)
+	}
+	if status.CheckStateId != checkStateID {
+		return nil, nil, fmt.Errorf("check status %d is of check state '%s', not '%s'", id, status.CheckStateId, checkStateID)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Check status of monitor '%s' on component '%s':\n\n", status.MonitorName, status.Component.Name))
+	sb.WriteString("| Field | Value |\n")
+	sb.WriteString("|---|---|\n")
+	sb.WriteString(fmt.Sprintf("| Health | %s |\n", status.Health))
+	sb.WriteString(fmt.Sprintf("| Triggered | %s |\n", formatTimestampMs(status.TriggeredTimestamp)))
+	sb.WriteString(fmt.Sprintf("| Component | %s (ID: %d, type: %s) |\n", status.Component.Name, status.Component.Id, status.Component.Type))
+	sb.WriteString(fmt.Sprintf("| Component Identifier | %s |\n", status.Component.Identifier))
+	sb.WriteString(fmt.Sprintf("| Monitor | %s (%v) |\n", status.MonitorName, status.MonitorId))
+	if status.MonitorDescription != "" {
+		sb.WriteString(fmt.Sprintf("| Monitor Description | %s |\n", strings.ReplaceAll(status.MonitorDescription, "\n", " ")))
+	}
+	sb.WriteString(fmt.Sprintf("| Message | %s |\n", strings.ReplaceAll(status.Message, "\n", " ")))
+	if status.Reason != "" {
+		sb.WriteString(fmt.Sprintf("| Reason | %s |\n", strings.ReplaceAll(status.Reason, "\n", " ")))
+	}
+
+	if status.TroubleshootingSteps != "" {
+		sb.WriteString("\nTroubleshooting steps:\n\n")
+		sb.WriteString(status.TroubleshootingSteps)
+		sb.WriteString("\n")
+	}
+
+	result := &CheckStatusResult{
+		CheckStateID:         status.CheckStateId,
+		Health:               status.Health,
+		MonitorName:          status.MonitorName,
+		MonitorDescription:   status.MonitorDescription,
+		ComponentID:          status.Component.Id,
+		ComponentName:        status.Component.Name,
+		ComponentType:        status.Component.Type,
+		ComponentIdentifier:  status.Component.Identifier,
+		Message:              status.Message,
+		Reason:               status.Reason,
+		TroubleshootingSteps: status.TroubleshootingSteps,
+		Metrics:              make([]CheckStatusMetric, 0, len(status.Metrics)),
+	}
+	if status.TriggeredTimestamp > 0 {
+		result.Triggered = time.UnixMilli(status.TriggeredTimestamp)
+	}
+	for _, m := range status.Metrics {
+		metric := CheckStatusMetric{Name: m.Name, Unit: m.Unit, Queries: make([]CheckStatusQuery, 0, len(m.Queries))}
+		for _, q := range m.Queries {
+			metric.Queries = append(metric.Queries, CheckStatusQuery{Alias: q.Alias, Query: q.Query})
+		}
+		result.Metrics = append(result.Metrics, metric)
+	}
+
+	if len(status.Metrics) > 0 {
+		sb.WriteString("\nMetrics:\n\n")
+		sb.WriteString("| Metric Name | Unit | Alias | Query |\n")
+		sb.WriteString("|---|---|---|---|\n")
+		for _, m := range status.Metrics {
+			unit := m.Unit
+			if unit == "" {
+				unit = "-"
+			}
+			for _, q := range m.Queries {
+				alias := q.Alias
+				if alias == "" {
+					alias = "-"
 				}
+				sb.WriteString(fmt.Sprintf("| %s | %s | %s | `%s` |\n", m.Name, unit, alias, q.Query))
 			}
 		}
-
-		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n", name, health, query, hint))
 	}
 
 	return &mcp.CallToolResult{
@@ -88,5 +461,112 @@ func (t tool) ListMonitors(ctx context.Context, request *mcp.CallToolRequest, pa
 				Text: sb.String(),
 			},
 		},
-	}, nil, nil
+	}, result, nil
+}
+
+// checkStatusID returns the ID of the check status of a check state, from the synced check states of its component
+func checkStatusID(syncedCheckStates []map[string]interface{}, checkStateID string) (int64, bool) {
+	for _, checkState := range syncedCheckStates {
+		if id, _ := checkState["checkStateId"].(string); id != checkStateID {
+			continue
+		}
+		if id, ok := checkState["id"].(float64); ok {
+			return int64(id), true
+		}
+	}
+	return 0, false
+}
+
+// parseSyncedCheckState extracts the monitor name, health, query and remediation hint of a synced check state
+func parseSyncedCheckState(checkStateData map[string]interface{}) ComponentMonitor {
+	var monitor ComponentMonitor
+
+	// Extract monitor name from check state data
+	if nameField, ok := checkStateData["name"].(string); ok {
+		monitor.Name = nameField
+	}
+
+	// Extract health
+	if healthField, ok := checkStateData["health"].(string); ok {
+		monitor.Health = healthField
+	}
+
+	if dataField, ok := checkStateData["data"].(map[string]interface{}); ok {
+		// Extract remediation hint
+		if remediationHint, ok := dataField["remediationHint"].(string); ok {
+			monitor.RemediationHint = remediationHint
+		}
+
+		// Extract query from displayTimeSeries
+		if displayTimeSeries, ok := dataField["displayTimeSeries"].([]interface{}); ok && len(displayTimeSeries) > 0 {
+			if series, ok := displayTimeSeries[0].(map[string]interface{}); ok {
+				if queries, ok := series["queries"].([]interface{}); ok && len(queries) > 0 {
+					if queryData, ok := queries[0].(map[string]interface{}); ok {
+						if q, ok := queryData["query"].(string); ok {
+							monitor.Query = q
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return monitor
+}
+
+func toMonitorOverview(m suseobservability.MonitorOverview) MonitorOverview {
+	rm := m.RuntimeMetrics
+	o := MonitorOverview{
+		ID:            m.Monitor.Id,
+		Name:          m.Monitor.Name,
+		Function:      m.Function.Name,
+		Status:        string(m.Monitor.Status),
+		RuntimeStatus: string(m.Monitor.RuntimeStatus),
+		Tags:          m.Monitor.Tags,
+		Critical:      rm.CriticalCount,
+		Deviating:     rm.DeviatingCount,
+		Clear:         rm.ClearCount,
+		Unknown:       rm.UnknownCount,
+	}
+	if rm.LastRunTimestamp > 0 {
+		o.LastRun = time.UnixMilli(rm.LastRunTimestamp)
+	}
+	if rm.LastFailedRunTimestamp > 0 {
+		o.LastFailedRun = time.UnixMilli(rm.LastFailedRunTimestamp)
+	}
+	for _, e := range m.Errors {
+		o.Errors = append(o.Errors, MonitorError{Level: e.Level, Count: e.Count, Error: e.Error})
+	}
+	return o
+}
+
+func hasAnyTag(tags []string, wanted []string) bool {
+	for _, t := range tags {
+		for _, w := range wanted {
+			if strings.EqualFold(t, w) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func runtimeStatusRank(s suseobservability.MonitorRuntimeStatusValue) int {
+	switch s {
+	case suseobservability.MonitorRuntimeStatusError:
+		return 0
+	case suseobservability.MonitorRuntimeStatusWarning:
+		return 1
+	case suseobservability.MonitorRuntimeStatusEnabled:
+		return 2
+	default:
+		return 3
+	}
+}
+
+func formatTimestampMs(ms int64) string {
+	if ms <= 0 {
+		return "-"
+	}
+	return time.UnixMilli(ms).Format(time.RFC3339)
 }
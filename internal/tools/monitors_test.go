@@ -0,0 +1,38 @@
+package tools
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCheckStatusID(t *testing.T) {
+	// Synced check states as decoded from the component, numbers being float64
+	var syncedCheckStates []map[string]interface{}
+	if err := json.Unmarshal([]byte(`[
+		{"id": 1001, "checkStateId": "cpu-usage", "health": "CLEAR"},
+		{"id": 1002, "checkStateId": "pod-restarts", "health": "CRITICAL"},
+		{"checkStateId": "external", "health": "DEVIATING"}
+	]`), &syncedCheckStates); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		checkStateID string
+		want         int64
+		wantOK       bool
+	}{
+		{checkStateID: "pod-restarts", want: 1002, wantOK: true},
+		{checkStateID: "cpu-usage", want: 1001, wantOK: true},
+		{checkStateID: "external"},
+		{checkStateID: "unknown"},
+		{checkStateID: "1002"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.checkStateID, func(t *testing.T) {
+			got, ok := checkStatusID(syncedCheckStates, tt.checkStateID)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("checkStatusID(%q) = %d, %t, want %d, %t", tt.checkStateID, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
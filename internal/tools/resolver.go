@@ -0,0 +1,154 @@
+package tools
+
+import (
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"suse-observability-mcp/client/suseobservability"
+)
+
+const nodeTypesRefreshInterval = 5 * time.Minute
+
+// nodeTypeResolver caches the component types, layers, domains and relation types of the instance
+// so topology results can show names instead of numeric IDs. It is loaded on first use and then
+// refreshed in the background when it is used after getting stale, so idle resolvers, such as the
+// ones of finished sessions, do not keep a goroutine running.
+type nodeTypeResolver struct {
+	client     *suseobservability.Client
+	interval   time.Duration
+	once       sync.Once
+	refreshing atomic.Bool
+
+	mu             sync.RWMutex
+	loadedAt       time.Time
+	componentTypes map[int64]suseobservability.NodeType
+	layers         map[int64]suseobservability.NodeType
+	domains        map[int64]suseobservability.NodeType
+	relationTypes  map[int64]suseobservability.NodeType
+	// errs holds the error of the last load of every kind that failed
+	errs map[string]error
+}
+
+func newNodeTypeResolver(c *suseobservability.Client, interval time.Duration) *nodeTypeResolver {
+	return &nodeTypeResolver{
+		client:   c,
+		interval: interval,
+		errs:     make(map[string]error),
+	}
+}
+
+// ensureLoaded loads the node types synchronously the first time, and refreshes them in the background once stale
+func (r *nodeTypeResolver) ensureLoaded() {
+	r.once.Do(r.refresh)
+
+	r.mu.RLock()
+	stale := time.Since(r.loadedAt) >= r.interval
+	r.mu.RUnlock()
+	if stale && r.refreshing.CompareAndSwap(false, true) {
+		go func() {
+			defer r.refreshing.Store(false)
+			r.refresh()
+		}()
+	}
+}
+
+// refresh reloads every node type, keeping the previous values of the ones that fail
+func (r *nodeTypeResolver) refresh() {
+	for _, kind := range []string{schemaComponentTypes, schemaLayers, schemaDomains, schemaRelationTypes} {
+		if err := r.load(kind); err != nil {
+			slog.Warn("failed to refresh node types", "type", kind, "error", err)
+		}
+	}
+
+	r.mu.Lock()
+	r.loadedAt = time.Now()
+	r.mu.Unlock()
+}
+
+// load fetches the nodes of the given kind, recording the error when it fails
+func (r *nodeTypeResolver) load(kind string) error {
+	fetch, dst := r.kind(kind)
+	nodes, err := fetch()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err != nil {
+		r.errs[kind] = err
+		return err
+	}
+	*dst = *nodes
+	delete(r.errs, kind)
+	return nil
+}
+
+// kind returns how to fetch the nodes of the given kind and where they are cached
+func (r *nodeTypeResolver) kind(kind string) (func() (*map[int64]suseobservability.NodeType, error), *map[int64]suseobservability.NodeType) {
+	switch kind {
+	case schemaComponentTypes:
+		return r.client.ComponentTypes, &r.componentTypes
+	case schemaLayers:
+		return r.client.Layers, &r.layers
+	case schemaDomains:
+		return r.client.Domains, &r.domains
+	default:
+		return r.client.RelationTypes, &r.relationTypes
+	}
+}
+
+func (r *nodeTypeResolver) ComponentType(id int64) string {
+	return r.lookup(&r.componentTypes, id)
+}
+
+func (r *nodeTypeResolver) Layer(id int64) string {
+	return r.lookup(&r.layers, id)
+}
+
+func (r *nodeTypeResolver) Domain(id int64) string {
+	return r.lookup(&r.domains, id)
+}
+
+func (r *nodeTypeResolver) RelationType(id int64) string {
+	return r.lookup(&r.relationTypes, id)
+}
+
+// lookup returns the name of the node, or its ID when unknown
+func (r *nodeTypeResolver) lookup(nodes *map[int64]suseobservability.NodeType, id int64) string {
+	r.ensureLoaded()
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if n, ok := (*nodes)[id]; ok && n.Name != "" {
+		return n.Name
+	}
+	return strconv.FormatInt(id, 10)
+}
+
+// List returns the cached nodes of the given kind sorted by name. When the kind failed to load and nothing is
+// cached, it is fetched again and the error returned if it still fails, so an empty list means there are none.
+func (r *nodeTypeResolver) List(kind string) ([]suseobservability.NodeType, error) {
+	r.ensureLoaded()
+	_, cached := r.kind(kind)
+	r.mu.RLock()
+	failed := len(*cached) == 0 && r.errs[kind] != nil
+	r.mu.RUnlock()
+	if failed {
+		if err := r.load(kind); err != nil {
+			return nil, err
+		}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	nodes := *cached
+	list := make([]suseobservability.NodeType, 0, len(nodes))
+	for _, n := range nodes {
+		list = append(list, n)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return strings.ToLower(list[i].Name) < strings.ToLower(list[j].Name)
+	})
+	return list, nil
+}
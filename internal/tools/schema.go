@@ -0,0 +1,130 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	schemaComponentTypes = "component_types"
+	schemaLayers         = "layers"
+	schemaDomains        = "domains"
+	schemaRelationTypes  = "relation_types"
+)
+
+type DescribeSchemaParams struct {
+	InstanceParams
+
+	Kinds  string `json:"kinds,omitempty" jsonschema:"What to list (comma-separated): 'component_types', 'layers', 'domains', 'relation_types'. Defaults to all."`
+	Search string `json:"search,omitempty" jsonschema:"Only list entries whose name, identifier or description contains this text (case-insensitive)"`
+}
+
+type SchemaEntry struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Identifier  string `json:"identifier,omitempty"`
+	Description string `json:"description,omitempty"`
+	OwnedBy     string `json:"owned_by,omitempty"`
+}
+
+// SchemaResult holds the entries of the requested kinds, the other kinds are left out
+type SchemaResult struct {
+	ComponentTypes []SchemaEntry `json:"component_types,omitempty"`
+	Layers         []SchemaEntry `json:"layers,omitempty"`
+	Domains        []SchemaEntry `json:"domains,omitempty"`
+	RelationTypes  []SchemaEntry `json:"relation_types,omitempty"`
+}
+
+// DescribeSchema lists the component types, layers, domains and relation types known to the instance
+func (t tool) DescribeSchema(ctx context.Context, request *mcp.CallToolRequest, params DescribeSchemaParams) (*mcp.CallToolResult, *SchemaResult, error) {
+	t, err := t.instance(params.Instance)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	titles := map[string]string{
+		schemaComponentTypes: "Component types",
+		schemaLayers:         "Layers",
+		schemaDomains:        "Domains (clusters)",
+		schemaRelationTypes:  "Relation types",
+	}
+
+	kinds := splitValues(params.Kinds)
+	if len(kinds) == 0 {
+		kinds = []string{schemaComponentTypes, schemaLayers, schemaDomains, schemaRelationTypes}
+	}
+	for _, k := range kinds {
+		if _, ok := titles[k]; !ok {
+			return nil, nil, fmt.Errorf("invalid kind '%s'. Must be 'component_types', 'layers', 'domains' or 'relation_types'", k)
+		}
+	}
+
+	search := strings.ToLower(params.Search)
+
+	result := &SchemaResult{}
+	entries := map[string]*[]SchemaEntry{
+		schemaComponentTypes: &result.ComponentTypes,
+		schemaLayers:         &result.Layers,
+		schemaDomains:        &result.Domains,
+		schemaRelationTypes:  &result.RelationTypes,
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Use the names below as values for the getComponents filters (types, domains).\n")
+	for _, k := range kinds {
+		nodes, err := t.nodeTypes.List(k)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list %s: %w", strings.ToLower(titles[k]), err)
+		}
+
+		var rows []string
+		for _, n := range nodes {
+			if search != "" &&
+				!strings.Contains(strings.ToLower(n.Name), search) &&
+				!strings.Contains(strings.ToLower(n.Identifier), search) &&
+				!strings.Contains(strings.ToLower(n.Description), search) {
+				continue
+			}
+			*entries[k] = append(*entries[k], SchemaEntry{ID: n.ID, Name: n.Name, Identifier: n.Identifier, Description: n.Description, OwnedBy: n.OwnedBy})
+
+			description := strings.ReplaceAll(n.Description, "\n", " ")
+			if len(description) > 150 {
+				description = description[:147] + "..."
+			}
+			if description == "" {
+				description = "-"
+			}
+			owner := n.OwnedBy
+			if owner == "" {
+				owner = "-"
+			}
+			identifier := n.Identifier
+			if identifier == "" {
+				identifier = "-"
+			}
+			rows = append(rows, fmt.Sprintf("| %s | %d | %s | %s | %s |\n", n.Name, n.ID, identifier, description, owner))
+		}
+
+		sb.WriteString(fmt.Sprintf("\n%s (%d):\n\n", titles[k], len(rows)))
+		if len(rows) == 0 {
+			sb.WriteString("None found.\n")
+			continue
+		}
+		sb.WriteString("| Name | ID | Identifier | Description | Owner |\n")
+		sb.WriteString("|---|---|---|---|---|\n")
+		for _, r := range rows {
+			sb.WriteString(r)
+		}
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: sb.String(),
+			},
+		},
+	}, result, nil
+}
@@ -0,0 +1,192 @@
+package tools
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"suse-observability-mcp/client/suseobservability"
+)
+
+// SeriesSummary describes a metric series without listing all of its points. Its labels are not encoded, MetricSeries carries them.
+type SeriesSummary struct {
+	Labels map[string]string `json:"-"`
+	Points int               `json:"points"`
+	Min    float64           `json:"min"`
+	MinAt  int64             `json:"min_at"`
+	Max    float64           `json:"max"`
+	MaxAt  int64             `json:"max_at"`
+	Mean   float64           `json:"mean"`
+	P50    float64           `json:"p50"`
+	P95    float64           `json:"p95"`
+	P99    float64           `json:"p99"`
+	First  float64           `json:"first"`
+	Last   float64           `json:"last"`
+	// Slope is the least squares trend of the series, in value per second
+	Slope float64 `json:"slope_per_second"`
+}
+
+func summarizeSeries(res suseobservability.MetricResult) SeriesSummary {
+	s := SeriesSummary{Labels: res.Labels}
+
+	var values []float64
+	var times []float64
+	for _, p := range res.Points {
+		if math.IsNaN(p.Value) || math.IsInf(p.Value, 0) {
+			continue
+		}
+		if len(values) == 0 || p.Value < s.Min {
+			s.Min, s.MinAt = p.Value, p.Timestamp
+		}
+		if len(values) == 0 || p.Value > s.Max {
+			s.Max, s.MaxAt = p.Value, p.Timestamp
+		}
+		values = append(values, p.Value)
+		times = append(times, float64(p.Timestamp))
+	}
+
+	s.Points = len(values)
+	if s.Points == 0 {
+		return s
+	}
+	s.First = values[0]
+	s.Last = values[len(values)-1]
+	s.Mean = mean(values)
+	s.Slope = linearSlope(times, values)
+
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	s.P50 = percentile(sorted, 50)
+	s.P95 = percentile(sorted, 95)
+	s.P99 = percentile(sorted, 99)
+
+	return s
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// percentile returns the p-th percentile of sorted values using linear interpolation
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := p / 100 * float64(len(sorted)-1)
+	lo := int(math.Floor(rank))
+	hi := int(math.Ceil(rank))
+	if lo == hi {
+		return sorted[lo]
+	}
+	return sorted[lo] + (sorted[hi]-sorted[lo])*(rank-float64(lo))
+}
+
+// linearSlope returns the least squares slope of ys over xs
+func linearSlope(xs, ys []float64) float64 {
+	if len(xs) < 2 {
+		return 0
+	}
+	mx, my := mean(xs), mean(ys)
+	var num, den float64
+	for i := range xs {
+		dx := xs[i] - mx
+		num += dx * (ys[i] - my)
+		den += dx * dx
+	}
+	if den == 0 {
+		return 0
+	}
+	return num / den
+}
+
+// stddev returns the population standard deviation of values
+func stddev(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	m := mean(values)
+	sum := 0.0
+	for _, v := range values {
+		sum += (v - m) * (v - m)
+	}
+	return math.Sqrt(sum / float64(len(values)))
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	return percentile(sorted, 50)
+}
+
+// pearson returns the correlation coefficient of xs and ys, or 0 when one of them is constant
+func pearson(xs, ys []float64) float64 {
+	if len(xs) < 2 {
+		return 0
+	}
+	mx, my := mean(xs), mean(ys)
+	var sxy, sxx, syy float64
+	for i := range xs {
+		dx, dy := xs[i]-mx, ys[i]-my
+		sxy += dx * dy
+		sxx += dx * dx
+		syy += dy * dy
+	}
+	if sxx == 0 || syy == 0 {
+		return 0
+	}
+	return sxy / math.Sqrt(sxx*syy)
+}
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline renders the points as a line of block characters, averaging them into at most width buckets.
+// Buckets without values are left blank.
+func sparkline(points []suseobservability.MetricPoint, width int) string {
+	if len(points) == 0 || width <= 0 {
+		return ""
+	}
+	if len(points) < width {
+		width = len(points)
+	}
+
+	buckets := make([]float64, width)
+	counts := make([]int, width)
+	for i, p := range points {
+		if math.IsNaN(p.Value) || math.IsInf(p.Value, 0) {
+			continue
+		}
+		b := i * width / len(points)
+		buckets[b] += p.Value
+		counts[b]++
+	}
+
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for i := range buckets {
+		if counts[i] == 0 {
+			continue
+		}
+		buckets[i] /= float64(counts[i])
+		lo = math.Min(lo, buckets[i])
+		hi = math.Max(hi, buckets[i])
+	}
+
+	var sb strings.Builder
+	for i, v := range buckets {
+		switch {
+		case counts[i] == 0:
+			sb.WriteRune(' ')
+		case hi == lo:
+			sb.WriteRune(sparkBlocks[len(sparkBlocks)/2])
+		default:
+			sb.WriteRune(sparkBlocks[int((v-lo)/(hi-lo)*float64(len(sparkBlocks)-1)+0.5)])
+		}
+	}
+	return sb.String()
+}
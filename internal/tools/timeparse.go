@@ -0,0 +1,152 @@
+package tools
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Supported time expressions:
+//   - 'now', 'today' (midnight UTC) and 'yesterday' (midnight UTC the day before)
+//   - an anchor with an offset, e.g. 'now-2h', 'today+9h', 'yesterday-30m'
+//   - a duration meaning "ago", e.g. '1h', '7d', '1w', '1d12h'
+//   - an RFC3339 timestamp ('2025-01-02T03:12:00Z'), a date-time without zone (UTC) or a date ('2025-01-02')
+//   - a Unix timestamp in seconds or milliseconds
+const timeFormatsHelp = "'now', 'today', 'yesterday', 'now-2h', a duration ago like '1h' or '7d', an RFC3339 timestamp or a Unix timestamp"
+
+var (
+	durationPattern  = regexp.MustCompile(`^(\d+(\.\d+)?(ns|us|µs|ms|s|m|h|d|w))+$`)
+	durationPart     = regexp.MustCompile(`(\d+(?:\.\d+)?)(ns|us|µs|ms|s|m|h|d|w)`)
+	anchoredPattern  = regexp.MustCompile(`^(now|today|yesterday)\s*(([+-])\s*(.+))?$`)
+	unixTimePattern  = regexp.MustCompile(`^\d{9,13}$`)
+	localTimeFormats = []string{"2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02T15:04", "2006-01-02 15:04", "2006-01-02"}
+)
+
+// parseTime parses a time expression relative to the current time
+func parseTime(s string) (time.Time, error) {
+	return parseTimeFrom(s, time.Now())
+}
+
+func parseTimeFrom(s string, now time.Time) (time.Time, error) {
+	expr := strings.ToLower(strings.TrimSpace(s))
+
+	if m := anchoredPattern.FindStringSubmatch(expr); m != nil {
+		anchor := now
+		switch m[1] {
+		case "today":
+			anchor = startOfDay(now)
+		case "yesterday":
+			anchor = startOfDay(now).AddDate(0, 0, -1)
+		}
+		if m[2] == "" {
+			return anchor, nil
+		}
+		d, err := parseDuration(strings.TrimSpace(m[4]))
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid time format: %s (expected %s)", s, timeFormatsHelp)
+		}
+		if m[3] == "-" {
+			d = -d
+		}
+		return anchor.Add(d), nil
+	}
+
+	if d, err := parseDuration(expr); err == nil {
+		return now.Add(-d), nil
+	}
+
+	if unixTimePattern.MatchString(expr) {
+		n, err := strconv.ParseInt(expr, 10, 64)
+		if err == nil {
+			if len(expr) > 10 {
+				return time.UnixMilli(n), nil
+			}
+			return time.Unix(n, 0), nil
+		}
+	}
+
+	trimmed := strings.TrimSpace(s)
+	if t, err := time.Parse(time.RFC3339Nano, trimmed); err == nil {
+		return t, nil
+	}
+	for _, layout := range localTimeFormats {
+		if t, err := time.ParseInLocation(layout, trimmed, time.UTC); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid time format: %s (expected %s)", s, timeFormatsHelp)
+}
+
+// parseDuration parses a Go duration extended with day (d) and week (w) units
+func parseDuration(s string) (time.Duration, error) {
+	if !durationPattern.MatchString(s) {
+		return 0, fmt.Errorf("invalid duration: %s", s)
+	}
+	var total time.Duration
+	for _, part := range durationPart.FindAllStringSubmatch(s, -1) {
+		value, unit := part[1], part[2]
+		switch unit {
+		case "d", "w":
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return 0, err
+			}
+			day := 24 * time.Hour
+			if unit == "w" {
+				day *= 7
+			}
+			total += time.Duration(n * float64(day))
+		default:
+			d, err := time.ParseDuration(value + unit)
+			if err != nil {
+				return 0, err
+			}
+			total += d
+		}
+	}
+	return total, nil
+}
+
+// startOfDay returns the midnight UTC of the day. Date-times without a zone are read in UTC as well,
+// so 'today' and '2025-01-02' do not depend on the zone of the host.
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// parseTimeRange parses optional start and end times, defaulting to the given duration ago and now
+func parseTimeRange(startParam, endParam, defaultStart string) (time.Time, time.Time, error) {
+	return parseTimeRangeFrom(startParam, endParam, defaultStart, time.Now())
+}
+
+func parseTimeRangeFrom(startParam, endParam, defaultStart string, now time.Time) (time.Time, time.Time, error) {
+	if startParam == "" {
+		startParam = defaultStart
+	}
+	if endParam == "" {
+		endParam = "now"
+	}
+	start, err := parseTimeFrom(startParam, now)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("failed to parse start time: %w", err)
+	}
+	end, err := parseTimeFrom(endParam, now)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("failed to parse end time: %w", err)
+	}
+	if start.After(end) {
+		return time.Time{}, time.Time{}, fmt.Errorf("start time %s is after end time %s", start.Format(time.RFC3339), end.Format(time.RFC3339))
+	}
+	return start, end, nil
+}
+
+// parseAt parses an optional point in time, returning the zero time when not set
+func parseAt(s string) (time.Time, error) {
+	if s == "" || s == "now" {
+		return time.Time{}, nil
+	}
+	return parseTime(s)
+}
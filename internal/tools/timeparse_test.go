@@ -0,0 +1,105 @@
+package tools
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimeFrom(t *testing.T) {
+	// 01:30 on March 10 in UTC+10 is still March 9 in UTC, the day 'today' must anchor on
+	now := time.Date(2025, 3, 10, 1, 30, 0, 0, time.FixedZone("UTC+10", 10*60*60))
+	today := time.Date(2025, 3, 9, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		expr    string
+		want    time.Time
+		wantErr bool
+	}{
+		{expr: "now", want: now},
+		{expr: " NOW ", want: now},
+		{expr: "today", want: today},
+		{expr: "yesterday", want: today.AddDate(0, 0, -1)},
+		{expr: "now-2h", want: now.Add(-2 * time.Hour)},
+		{expr: "now + 30m", want: now.Add(30 * time.Minute)},
+		{expr: "today+9h", want: today.Add(9 * time.Hour)},
+		{expr: "yesterday-30m", want: today.AddDate(0, 0, -1).Add(-30 * time.Minute)},
+		{expr: "today+1d", want: today.Add(24 * time.Hour)},
+		{expr: "1h", want: now.Add(-time.Hour)},
+		{expr: "7d", want: now.Add(-7 * 24 * time.Hour)},
+		{expr: "1w", want: now.Add(-7 * 24 * time.Hour)},
+		{expr: "1d12h", want: now.Add(-36 * time.Hour)},
+		{expr: "1.5h", want: now.Add(-90 * time.Minute)},
+		{expr: "2025-01-02T03:12:00Z", want: time.Date(2025, 1, 2, 3, 12, 0, 0, time.UTC)},
+		{expr: "2025-01-02T03:12:00+02:00", want: time.Date(2025, 1, 2, 1, 12, 0, 0, time.UTC)},
+		{expr: "2025-01-02T03:12:00.5Z", want: time.Date(2025, 1, 2, 3, 12, 0, 500_000_000, time.UTC)},
+		{expr: "2025-01-02 03:12", want: time.Date(2025, 1, 2, 3, 12, 0, 0, time.UTC)},
+		{expr: "2025-01-02T03:12:05", want: time.Date(2025, 1, 2, 3, 12, 5, 0, time.UTC)},
+		{expr: "2025-01-02", want: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)},
+		// Unix timestamps: up to 10 digits are seconds, 11 to 13 digits milliseconds
+		{expr: "100000000", want: time.Unix(100000000, 0)},
+		{expr: "1735787520", want: time.Unix(1735787520, 0)},
+		{expr: "17357875200", want: time.UnixMilli(17357875200)},
+		{expr: "1735787520000", want: time.UnixMilli(1735787520000)},
+		{expr: "12345678", wantErr: true},
+		{expr: "17357875200000", wantErr: true},
+		{expr: "", wantErr: true},
+		{expr: "later", wantErr: true},
+		{expr: "now-", wantErr: true},
+		{expr: "now-2x", wantErr: true},
+		{expr: "tomorrow", wantErr: true},
+		{expr: "2025-13-01", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := parseTimeFrom(tt.expr, now)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseTimeFrom(%q) = %v, want an error", tt.expr, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseTimeFrom(%q) failed: %v", tt.expr, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("parseTimeFrom(%q) = %v, want %v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTimeRangeFrom(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		start, end string
+		wantStart  time.Time
+		wantEnd    time.Time
+		wantErr    bool
+	}{
+		{name: "defaults", wantStart: now.Add(-time.Hour), wantEnd: now},
+		{name: "explicit", start: "2025-03-09", end: "today", wantStart: time.Date(2025, 3, 9, 0, 0, 0, 0, time.UTC), wantEnd: time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)},
+		{name: "equal", start: "now", end: "now", wantStart: now, wantEnd: now},
+		{name: "start after end", start: "1h", end: "2h", wantErr: true},
+		{name: "invalid start", start: "soon", wantErr: true},
+		{name: "invalid end", end: "soon", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, err := parseTimeRangeFrom(tt.start, tt.end, "1h", now)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseTimeRangeFrom(%q, %q) = %v, %v, want an error", tt.start, tt.end, start, end)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseTimeRangeFrom(%q, %q) failed: %v", tt.start, tt.end, err)
+			}
+			if !start.Equal(tt.wantStart) || !end.Equal(tt.wantEnd) {
+				t.Errorf("parseTimeRangeFrom(%q, %q) = %v, %v, want %v, %v", tt.start, tt.end, start, end, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
@@ -1,16 +1,145 @@
 package tools
 
 import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
 	"suse-observability-mcp/client/suseobservability"
 )
 
+const defaultQueryTimeout = "30s"
+
+// defaultWindows is how far back the tools look when no start time is given, by tool name
+var defaultWindows = map[string]string{
+	"compareTopology":      "1h",
+	"investigateComponent": "2h",
+	"listMetrics":          "1h",
+	"getMetrics":           "1h",
+	"searchMetricNames":    "1h",
+	"getMetricLabels":      "1h",
+	"detectAnomalies":      "6h",
+	"correlateMetrics":     "2h",
+	"getEvents":            "1h",
+	"getEvent":             "24h",
+	"searchTraces":         "1h",
+}
+
+// Options tune the tools
+type Options struct {
+	// QueryTimeout is the timeout of the PromQL queries, enforced by SUSE Observability
+	QueryTimeout string
+	// DefaultWindows overrides the default start time of the tools, by tool name
+	DefaultWindows map[string]string
+}
+
+// Validate checks the query timeout, and that the default windows are time expressions of tools that take a start time
+func (o Options) Validate() error {
+	if o.QueryTimeout != "" {
+		if _, err := parseStep(o.QueryTimeout); err != nil {
+			return fmt.Errorf("invalid query timeout: %w", err)
+		}
+	}
+	for name, window := range o.DefaultWindows {
+		if _, ok := defaultWindows[name]; !ok {
+			return fmt.Errorf("tool %q has no default window, tools with one are: %s", name, strings.Join(slices.Sorted(maps.Keys(defaultWindows)), ", "))
+		}
+		if _, err := parseTime(window); err != nil {
+			return fmt.Errorf("invalid default window of %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Instance is a SUSE Observability instance the tools can query
+type Instance struct {
+	Name   string
+	Client *suseobservability.Client
+}
+
+// InstanceParams selects the instance a tool queries. Every tool embeds it.
+type InstanceParams struct {
+	Instance string `json:"instance,omitempty" jsonschema:"Name of the SUSE Observability instance to query. Defaults to the default instance."`
+}
+
 type tool struct {
-	client *suseobservability.Client
+	instanceName string
+	client       *suseobservability.Client
+	nodeTypes    *nodeTypeResolver
+	queryTimeout string
+	windows      map[string]string
+	// instances are the tools of every instance, the default one first
+	instances []*tool
+}
+
+// NewBaseTool returns the tools of the first instance, which is the default one. The instance argument of
+// every tool runs it on another instance. Without instances, the tools can be registered to list them,
+// such as when validating a configuration, but not called.
+func NewBaseTool(instances []Instance, opts Options) (t *tool) {
+	queryTimeout := opts.QueryTimeout
+	if queryTimeout == "" {
+		queryTimeout = defaultQueryTimeout
+	}
+	windows := make(map[string]string, len(defaultWindows))
+	for name, window := range defaultWindows {
+		windows[name] = window
+	}
+	for name, window := range opts.DefaultWindows {
+		windows[name] = window
+	}
+	if len(instances) == 0 {
+		return &tool{queryTimeout: queryTimeout, windows: windows}
+	}
+
+	all := make([]*tool, 0, len(instances))
+	for _, i := range instances {
+		all = append(all, &tool{
+			instanceName: i.Name,
+			client:       i.Client,
+			nodeTypes:    newNodeTypeResolver(i.Client, nodeTypesRefreshInterval),
+			queryTimeout: queryTimeout,
+			windows:      windows,
+		})
+	}
+	for _, i := range all {
+		i.instances = all
+	}
+	return all[0]
+}
+
+// instance returns the tools of the named instance, or t itself when no instance is named
+func (t tool) instance(name string) (tool, error) {
+	if name == "" || name == t.instanceName {
+		return t, nil
+	}
+	for _, i := range t.instances {
+		if i.instanceName == name {
+			return *i, nil
+		}
+	}
+	return tool{}, fmt.Errorf("unknown instance '%s'. Must be one of: %s", name, strings.Join(t.instanceNames(), ", "))
+}
+
+func (t tool) instanceNames() []string {
+	names := make([]string, 0, len(t.instances))
+	for _, i := range t.instances {
+		names = append(names, i.instanceName)
+	}
+	return names
 }
 
-// NewFactory returns a tool factory
-func NewBaseTool(c *suseobservability.Client) (t *tool) {
-	t = new(tool)
-	t.client = c
-	return
+// splitValues splits a comma-separated argument into its trimmed, non-empty values
+func splitValues(values string) []string {
+	if values == "" {
+		return nil
+	}
+	var out []string
+	for _, v := range strings.Split(values, ",") {
+		v = strings.TrimSpace(v)
+		if v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
 }
@@ -3,14 +3,17 @@ package tools
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	"suse-observability-mcp/client/suseobservability"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
-type GetComponentsParams struct {
+// TopologyFilterParams are the STQL filters shared by every tool that selects topology components
+type TopologyFilterParams struct {
 	// Filters - all support multiple comma-separated values
 	Names        string `json:"names,omitempty" jsonschema:"Component names to match (comma-separated for multiple values, e.g., 'checkout-service,redis-master')"`
 	Types        string `json:"types,omitempty" jsonschema:"Component types to filter (comma-separated, e.g., 'pod,service,deployment')"`
@@ -24,15 +27,504 @@ type GetComponentsParams struct {
 	WithNeighborsDirection string `json:"with_neighbors_direction,omitempty" jsonschema:"Direction: 'up', 'down', or 'both' for withNeighborsOf,default=both"`
 }
 
+type GetComponentsParams struct {
+	InstanceParams
+	TopologyFilterParams
+
+	// Point in time of the topology
+	At string `json:"at,omitempty" jsonschema:"Query the topology as it was at this time: RFC3339 timestamp (e.g. '2025-01-02T03:12:00Z'), Unix timestamp, 'yesterday+9h' or duration ago (e.g. '2h'). Defaults to now."`
+
+	// Output format
+	Output string `json:"output,omitempty" jsonschema:"Output format: 'table', 'graph' (nodes and adjacency list with relation types), 'mermaid' or 'dot' (graph plus diagram),default=table"`
+}
+
+type CompareTopologyParams struct {
+	InstanceParams
+	TopologyFilterParams
+
+	// Raw STQL query, used instead of the filters above when provided
+	Query string `json:"query,omitempty" jsonschema:"Raw STQL topology query. Overrides the other component filters."`
+
+	From string `json:"from,omitempty" jsonschema:"Earlier point in time: RFC3339 timestamp, Unix timestamp, 'now-1h' or duration ago (e.g. '1h', '1d'),default=1h"`
+	To   string `json:"to,omitempty" jsonschema:"Later point in time: 'now', RFC3339 timestamp, Unix timestamp or duration ago,default=now"`
+}
+
 type Component struct {
-	ID    int64  `json:"id"`
-	Name  string `json:"name"`
-	Type  string `json:"type"`
-	State string `json:"state,omitempty"`
+	ID     int64  `json:"id"`
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Layer  string `json:"layer,omitempty"`
+	Domain string `json:"domain,omitempty"`
+	State  string `json:"state,omitempty"`
+}
+
+type Relation struct {
+	ID     int64  `json:"id"`
+	Source int64  `json:"source"`
+	Target int64  `json:"target"`
+	Type   string `json:"type,omitempty"`
+}
+
+type ComponentsResult struct {
+	Query      string      `json:"query"`
+	At         time.Time   `json:"at,omitzero"`
+	Components []Component `json:"components"`
+	// Only set for the graph outputs
+	Relations []Relation `json:"relations,omitempty"`
+}
+
+type HealthStateChange struct {
+	Component Component `json:"component"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+}
+
+type RelationChange struct {
+	ID int64 `json:"id"`
+	// The IDs of the endpoints are 0 when unknown, the names empty when outside the query result
+	SourceID int64  `json:"source_id,omitempty"`
+	TargetID int64  `json:"target_id,omitempty"`
+	Source   string `json:"source,omitempty"`
+	Target   string `json:"target,omitempty"`
+	Type     string `json:"type,omitempty"`
+}
+
+// ChangedRelation is a relation whose endpoints or type changed under the same ID
+type ChangedRelation struct {
+	Before RelationChange `json:"before"`
+	After  RelationChange `json:"after"`
+}
+
+type TopologyDiffResult struct {
+	Query            string              `json:"query"`
+	From             time.Time           `json:"from"`
+	To               time.Time           `json:"to"`
+	Before           int                 `json:"components_before"`
+	After            int                 `json:"components_after"`
+	Added            []Component         `json:"added"`
+	Removed          []Component         `json:"removed"`
+	HealthChanged    []HealthStateChange `json:"health_changed"`
+	AddedRelations   []RelationChange    `json:"added_relations"`
+	RemovedRelations []RelationChange    `json:"removed_relations"`
+	ChangedRelations []ChangedRelation   `json:"changed_relations"`
 }
 
 // GetComponents searches for topology components using STQL filters
-func (t tool) GetComponents(ctx context.Context, request *mcp.CallToolRequest, params GetComponentsParams) (*mcp.CallToolResult, any, error) {
+func (t tool) GetComponents(ctx context.Context, request *mcp.CallToolRequest, params GetComponentsParams) (*mcp.CallToolResult, *ComponentsResult, error) {
+	t, err := t.instance(params.Instance)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	query, err := buildTopologyQuery(params.TopologyFilterParams)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	at, err := parseAt(params.At)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse at time: %w", err)
+	}
+
+	switch params.Output {
+	case "", "table":
+	case "graph", "mermaid", "dot":
+		return t.getComponentsGraph(ctx, params, query, at)
+	default:
+		return nil, nil, fmt.Errorf("invalid output '%s'. Must be 'table', 'graph', 'mermaid' or 'dot'", params.Output)
+	}
+
+	// Execute topology query
+	components, err := t.client.SnapShotTopologyQueryAt(ctx, query, at)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query topology (STQL: %s): %w", query, err)
+	}
+
+	table := formatComponentsTable(components, params, query, t.nodeTypes)
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: table,
+			},
+		},
+	}, &ComponentsResult{Query: query, At: at, Components: toComponents(components, t.nodeTypes)}, nil
+}
+
+// CompareTopology runs the same topology query at two points in time and reports the differences
+func (t tool) CompareTopology(ctx context.Context, request *mcp.CallToolRequest, params CompareTopologyParams) (*mcp.CallToolResult, *TopologyDiffResult, error) {
+	t, err := t.instance(params.Instance)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	query := params.Query
+	if query == "" {
+		var err error
+		query, err = buildTopologyQuery(params.TopologyFilterParams)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w (or provide a raw STQL query)", err)
+		}
+	}
+
+	fromParam := params.From
+	if fromParam == "" {
+		fromParam = t.windows["compareTopology"]
+	}
+	from, err := parseTime(fromParam)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse from time: %w", err)
+	}
+	to, err := parseAt(params.To)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse to time: %w", err)
+	}
+	end := to
+	if end.IsZero() {
+		end = time.Now()
+	}
+	if from.After(end) {
+		return nil, nil, fmt.Errorf("from time %s is after to time %s", from.Format(time.RFC3339), end.Format(time.RFC3339))
+	}
+
+	before, err := t.client.SnapShotTopologyGraph(ctx, query, from)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query topology at %s (STQL: %s): %w", from.Format(time.RFC3339), query, err)
+	}
+	after, err := t.client.SnapShotTopologyGraph(ctx, query, to)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query topology at %s (STQL: %s): %w", params.To, query, err)
+	}
+
+	to = end
+	diff := diffTopology(before, after)
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: formatTopologyDiff(diff, from, to, query, t.nodeTypes),
+			},
+		},
+	}, topologyDiffResult(diff, from, to, query, t.nodeTypes), nil
+}
+
+type healthChange struct {
+	Component suseobservability.ViewComponent
+	From      string
+	To        string
+}
+
+type relationRef struct {
+	ID       int64
+	SourceID int64
+	TargetID int64
+	Source   string
+	Target   string
+	// TypeID is 0 when unknown
+	TypeID int64
+}
+
+// changedFrom tells whether the endpoints or the type of the relation differ from the earlier one.
+// Endpoints and types unknown in either snapshot are not compared.
+func (r relationRef) changedFrom(old relationRef) bool {
+	differ := func(a, b int64) bool { return a != 0 && b != 0 && a != b }
+	return differ(r.SourceID, old.SourceID) || differ(r.TargetID, old.TargetID) || differ(r.TypeID, old.TypeID)
+}
+
+type relationChange struct {
+	Before relationRef
+	After  relationRef
+}
+
+type topologyDiff struct {
+	Before           int
+	After            int
+	Added            []suseobservability.ViewComponent
+	Removed          []suseobservability.ViewComponent
+	HealthChanged    []healthChange
+	AddedRelations   []relationRef
+	RemovedRelations []relationRef
+	ChangedRelations []relationChange
+}
+
+func diffTopology(beforeSnapshot, afterSnapshot *suseobservability.ViewSnapshotResponse) topologyDiff {
+	before, after := beforeSnapshot.Components, afterSnapshot.Components
+	diff := topologyDiff{Before: len(before), After: len(after)}
+
+	beforeByID := make(map[int64]suseobservability.ViewComponent, len(before))
+	for _, c := range before {
+		beforeByID[c.ID] = c
+	}
+	afterByID := make(map[int64]suseobservability.ViewComponent, len(after))
+	for _, c := range after {
+		afterByID[c.ID] = c
+	}
+
+	for _, c := range after {
+		old, ok := beforeByID[c.ID]
+		if !ok {
+			diff.Added = append(diff.Added, c)
+			continue
+		}
+		if old.State.HealthState != c.State.HealthState {
+			diff.HealthChanged = append(diff.HealthChanged, healthChange{Component: c, From: old.State.HealthState, To: c.State.HealthState})
+		}
+	}
+	for _, c := range before {
+		if _, ok := afterByID[c.ID]; !ok {
+			diff.Removed = append(diff.Removed, c)
+		}
+	}
+
+	beforeRelations := indexRelations(beforeSnapshot)
+	afterRelations := indexRelations(afterSnapshot)
+	for id, r := range afterRelations {
+		old, ok := beforeRelations[id]
+		switch {
+		case !ok:
+			diff.AddedRelations = append(diff.AddedRelations, r)
+		case r.changedFrom(old):
+			diff.ChangedRelations = append(diff.ChangedRelations, relationChange{Before: old, After: r})
+		}
+	}
+	for id, r := range beforeRelations {
+		if _, ok := afterRelations[id]; !ok {
+			diff.RemovedRelations = append(diff.RemovedRelations, r)
+		}
+	}
+	byID := func(refs []relationRef) {
+		sort.Slice(refs, func(i, j int) bool { return refs[i].ID < refs[j].ID })
+	}
+	byID(diff.AddedRelations)
+	byID(diff.RemovedRelations)
+	sort.Slice(diff.ChangedRelations, func(i, j int) bool { return diff.ChangedRelations[i].After.ID < diff.ChangedRelations[j].After.ID })
+
+	return diff
+}
+
+// indexRelations resolves the endpoints and types of the relations of the snapshot. When the snapshot carries
+// no relations, they are derived from the relation IDs of the components, without their types and with the
+// endpoints outside of the query result left empty.
+func indexRelations(snapshot *suseobservability.ViewSnapshotResponse) map[int64]relationRef {
+	names := make(map[int64]string, len(snapshot.Components))
+	for _, c := range snapshot.Components {
+		names[c.ID] = c.Name
+	}
+
+	relations := make(map[int64]relationRef)
+	if len(snapshot.Relations) > 0 {
+		for _, r := range snapshot.Relations {
+			relations[r.ID] = relationRef{ID: r.ID, SourceID: r.Source, TargetID: r.Target, Source: names[r.Source], Target: names[r.Target], TypeID: r.Type}
+		}
+		return relations
+	}
+	for _, c := range snapshot.Components {
+		for _, id := range c.OutgoingRelations {
+			r := relations[id]
+			r.ID = id
+			r.SourceID, r.Source = c.ID, c.Name
+			relations[id] = r
+		}
+		for _, id := range c.IncomingRelations {
+			r := relations[id]
+			r.ID = id
+			r.TargetID, r.Target = c.ID, c.Name
+			relations[id] = r
+		}
+	}
+	return relations
+}
+
+func topologyDiffResult(diff topologyDiff, from, to time.Time, query string, nodeTypes *nodeTypeResolver) *TopologyDiffResult {
+	res := &TopologyDiffResult{
+		Query:            query,
+		From:             from,
+		To:               to,
+		Before:           diff.Before,
+		After:            diff.After,
+		Added:            toComponents(diff.Added, nodeTypes),
+		Removed:          toComponents(diff.Removed, nodeTypes),
+		HealthChanged:    make([]HealthStateChange, 0, len(diff.HealthChanged)),
+		AddedRelations:   make([]RelationChange, 0, len(diff.AddedRelations)),
+		RemovedRelations: make([]RelationChange, 0, len(diff.RemovedRelations)),
+		ChangedRelations: make([]ChangedRelation, 0, len(diff.ChangedRelations)),
+	}
+	for _, h := range diff.HealthChanged {
+		res.HealthChanged = append(res.HealthChanged, HealthStateChange{Component: toComponent(h.Component, nodeTypes), From: h.From, To: h.To})
+	}
+	for _, r := range diff.AddedRelations {
+		res.AddedRelations = append(res.AddedRelations, toRelationChange(r, nodeTypes))
+	}
+	for _, r := range diff.RemovedRelations {
+		res.RemovedRelations = append(res.RemovedRelations, toRelationChange(r, nodeTypes))
+	}
+	for _, r := range diff.ChangedRelations {
+		res.ChangedRelations = append(res.ChangedRelations, ChangedRelation{Before: toRelationChange(r.Before, nodeTypes), After: toRelationChange(r.After, nodeTypes)})
+	}
+	return res
+}
+
+func toRelationChange(r relationRef, nodeTypes *nodeTypeResolver) RelationChange {
+	change := RelationChange{ID: r.ID, SourceID: r.SourceID, TargetID: r.TargetID, Source: r.Source, Target: r.Target}
+	if r.TypeID != 0 {
+		change.Type = nodeTypes.RelationType(r.TypeID)
+	}
+	return change
+}
+
+func formatTopologyDiff(diff topologyDiff, from, to time.Time, query string, nodeTypes *nodeTypeResolver) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Topology changes between %s (%d component(s)) and %s (%d component(s)) for query: %s\n\n",
+		from.Format(time.RFC3339), diff.Before, to.Format(time.RFC3339), diff.After, query))
+
+	if len(diff.Added) == 0 && len(diff.Removed) == 0 && len(diff.HealthChanged) == 0 &&
+		len(diff.AddedRelations) == 0 && len(diff.RemovedRelations) == 0 && len(diff.ChangedRelations) == 0 {
+		sb.WriteString("No differences found.\n")
+		return sb.String()
+	}
+
+	writeComponents := func(title string, components []suseobservability.ViewComponent) {
+		if len(components) == 0 {
+			return
+		}
+		sb.WriteString(fmt.Sprintf("%s (%d):\n\n", title, len(components)))
+		sb.WriteString("| Component Name | ID | Type | Domain | State |\n")
+		sb.WriteString("|---|---|---|---|---|\n")
+		for _, c := range components {
+			sb.WriteString(fmt.Sprintf("| %s | %d | %s | %s | %s |\n", c.Name, c.ID,
+				nodeTypes.ComponentType(c.Type), nodeTypes.Domain(int64(c.Domain)), c.State.HealthState))
+		}
+		sb.WriteString("\n")
+	}
+	writeComponents("Added components", diff.Added)
+	writeComponents("Removed components", diff.Removed)
+
+	if len(diff.HealthChanged) > 0 {
+		sb.WriteString(fmt.Sprintf("Health state changes (%d):\n\n", len(diff.HealthChanged)))
+		sb.WriteString("| Component Name | ID | Before | After |\n")
+		sb.WriteString("|---|---|---|---|\n")
+		for _, h := range diff.HealthChanged {
+			sb.WriteString(fmt.Sprintf("| %s | %d | %s | %s |\n", h.Component.Name, h.Component.ID, h.From, h.To))
+		}
+		sb.WriteString("\n")
+	}
+
+	endpoint := func(name string, id int64) string {
+		switch {
+		case name != "":
+			return name
+		case id != 0:
+			return fmt.Sprintf("%d (outside query)", id)
+		default:
+			return "(outside query)"
+		}
+	}
+	relationType := func(r relationRef) string {
+		if r.TypeID == 0 {
+			return "-"
+		}
+		return nodeTypes.RelationType(r.TypeID)
+	}
+	writeRelations := func(title string, relations []relationRef) {
+		if len(relations) == 0 {
+			return
+		}
+		sb.WriteString(fmt.Sprintf("%s (%d):\n\n", title, len(relations)))
+		sb.WriteString("| Relation ID | Source | Target | Type |\n")
+		sb.WriteString("|---|---|---|---|\n")
+		for _, r := range relations {
+			sb.WriteString(fmt.Sprintf("| %d | %s | %s | %s |\n", r.ID, endpoint(r.Source, r.SourceID), endpoint(r.Target, r.TargetID), relationType(r)))
+		}
+		sb.WriteString("\n")
+	}
+	writeRelations("Added relations", diff.AddedRelations)
+	writeRelations("Removed relations", diff.RemovedRelations)
+
+	if len(diff.ChangedRelations) > 0 {
+		sb.WriteString(fmt.Sprintf("Changed relations (%d):\n\n", len(diff.ChangedRelations)))
+		sb.WriteString("| Relation ID | Before | After |\n")
+		sb.WriteString("|---|---|---|\n")
+		for _, c := range diff.ChangedRelations {
+			describe := func(r relationRef) string {
+				return fmt.Sprintf("%s -> %s (%s)", endpoint(r.Source, r.SourceID), endpoint(r.Target, r.TargetID), relationType(r))
+			}
+			sb.WriteString(fmt.Sprintf("| %d | %s | %s |\n", c.After.ID, describe(c.Before), describe(c.After)))
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+func formatComponentsTable(components []suseobservability.ViewComponent, params GetComponentsParams, query string, nodeTypes *nodeTypeResolver) string {
+	if len(components) == 0 {
+		return fmt.Sprintf("No components found for query: %s", query)
+	}
+
+	var sb strings.Builder
+
+	// Summary
+	sb.WriteString(fmt.Sprintf("Found %d component(s)", len(components)))
+
+	filters := []string{}
+	if params.Names != "" {
+		filters = append(filters, fmt.Sprintf("names: %s", params.Names))
+	}
+	if params.Types != "" {
+		filters = append(filters, fmt.Sprintf("types: %s", params.Types))
+	}
+	if params.HealthStates != "" {
+		filters = append(filters, fmt.Sprintf("healthstates: %s", params.HealthStates))
+	}
+	if params.Domains != "" {
+		filters = append(filters, fmt.Sprintf("domains: %s", params.Domains))
+	}
+	if params.Namespace != "" {
+		filters = append(filters, fmt.Sprintf("namespace: %s", params.Namespace))
+	}
+	if params.At != "" {
+		filters = append(filters, fmt.Sprintf("at: %s", params.At))
+	}
+	if len(filters) > 0 {
+		sb.WriteString(" (" + strings.Join(filters, ", ") + ")")
+	}
+	sb.WriteString(":\n\n")
+
+	// Header
+	sb.WriteString("| Component Name | ID | Type | Layer | Domain | State |\n")
+	sb.WriteString("|---|---|---|---|---|---|\n")
+
+	// Data rows
+	for _, c := range components {
+		sb.WriteString(fmt.Sprintf("| %s | %d | %s | %s | %s | %s |\n", c.Name, c.ID,
+			nodeTypes.ComponentType(c.Type), nodeTypes.Layer(int64(c.Layer)), nodeTypes.Domain(int64(c.Domain)), c.State.HealthState))
+	}
+
+	return sb.String()
+}
+
+// toComponent resolves the type, layer and domain names of a component
+func toComponent(c suseobservability.ViewComponent, nodeTypes *nodeTypeResolver) Component {
+	return Component{
+		ID:     c.ID,
+		Name:   c.Name,
+		Type:   nodeTypes.ComponentType(c.Type),
+		Layer:  nodeTypes.Layer(int64(c.Layer)),
+		Domain: nodeTypes.Domain(int64(c.Domain)),
+		State:  c.State.HealthState,
+	}
+}
+
+func toComponents(components []suseobservability.ViewComponent, nodeTypes *nodeTypeResolver) []Component {
+	out := make([]Component, 0, len(components))
+	for _, c := range components {
+		out = append(out, toComponent(c, nodeTypes))
+	}
+	return out
+}
+
+// buildTopologyQuery builds an STQL query from the topology filters
+func buildTopologyQuery(params TopologyFilterParams) (string, error) {
 	var query string
 
 	// Build STQL query from parameters using IN/NOT IN operators
@@ -90,7 +582,7 @@ func (t tool) GetComponents(ctx context.Context, request *mcp.CallToolRequest, p
 	// Add withNeighborsOf if requested
 	if params.WithNeighbors {
 		if query == "" {
-			return nil, nil, fmt.Errorf("with_neighbors requires at least one filter to define the components")
+			return "", fmt.Errorf("with_neighbors requires at least one filter to define the components")
 		}
 
 		// Set defaults for levels and direction
@@ -106,7 +598,7 @@ func (t tool) GetComponents(ctx context.Context, request *mcp.CallToolRequest, p
 		// Validate direction
 		validDirections := map[string]bool{"up": true, "down": true, "both": true}
 		if !validDirections[direction] {
-			return nil, nil, fmt.Errorf("invalid with_neighbors_direction '%s'. Must be 'up', 'down', or 'both'", direction)
+			return "", fmt.Errorf("invalid with_neighbors_direction '%s'. Must be 'up', 'down', or 'both'", direction)
 		}
 
 		// Build withNeighborsOf function
@@ -116,65 +608,8 @@ func (t tool) GetComponents(ctx context.Context, request *mcp.CallToolRequest, p
 	}
 
 	if query == "" {
-		return nil, nil, fmt.Errorf("at least one filter (names, types, healthstates, domains, namespace) must be provided")
-	}
-
-	// Execute topology query
-	components, err := t.client.SnapShotTopologyQuery(ctx, query)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to query topology (STQL: %s): %w", query, err)
+		return "", fmt.Errorf("at least one filter (names, types, healthstates, domains, namespace) must be provided")
 	}
 
-	table := formatComponentsTable(components, params, query)
-
-	return &mcp.CallToolResult{
-		Content: []mcp.Content{
-			&mcp.TextContent{
-				Text: table,
-			},
-		},
-	}, nil, nil
-}
-
-func formatComponentsTable(components []suseobservability.ViewComponent, params GetComponentsParams, query string) string {
-	if len(components) == 0 {
-		return fmt.Sprintf("No components found for query: %s", query)
-	}
-
-	var sb strings.Builder
-
-	// Summary
-	sb.WriteString(fmt.Sprintf("Found %d component(s)", len(components)))
-
-	filters := []string{}
-	if params.Names != "" {
-		filters = append(filters, fmt.Sprintf("names: %s", params.Names))
-	}
-	if params.Types != "" {
-		filters = append(filters, fmt.Sprintf("types: %s", params.Types))
-	}
-	if params.HealthStates != "" {
-		filters = append(filters, fmt.Sprintf("healthstates: %s", params.HealthStates))
-	}
-	if params.Domains != "" {
-		filters = append(filters, fmt.Sprintf("domains: %s", params.Domains))
-	}
-	if params.Namespace != "" {
-		filters = append(filters, fmt.Sprintf("namespace: %s", params.Namespace))
-	}
-	if len(filters) > 0 {
-		sb.WriteString(" (" + strings.Join(filters, ", ") + ")")
-	}
-	sb.WriteString(":\n\n")
-
-	// Header
-	sb.WriteString("| Component Name | ID | State |\n")
-	sb.WriteString("|---|---|---|\n")
-
-	// Data rows
-	for _, c := range components {
-		sb.WriteString(fmt.Sprintf("| %s | %d | %s |\n", c.Name, c.ID, c.State.HealthState))
-	}
-
-	return sb.String()
+	return query, nil
 }
@@ -0,0 +1,67 @@
+package tools
+
+import (
+	"testing"
+
+	"suse-observability-mcp/client/suseobservability"
+)
+
+func TestDiffTopologyRelations(t *testing.T) {
+	components := []suseobservability.ViewComponent{{ID: 1, Name: "checkout"}, {ID: 2, Name: "payment"}, {ID: 3, Name: "cart"}}
+	before := &suseobservability.ViewSnapshotResponse{
+		Components: components,
+		Relations: []suseobservability.ViewRelation{
+			{ID: 10, Source: 1, Target: 2, Type: 100},
+			{ID: 11, Source: 1, Target: 3, Type: 100},
+			{ID: 12, Source: 2, Target: 3, Type: 100},
+			{ID: 13, Source: 3, Target: 2, Type: 100},
+		},
+	}
+	after := &suseobservability.ViewSnapshotResponse{
+		Components: components,
+		Relations: []suseobservability.ViewRelation{
+			// Unchanged
+			{ID: 10, Source: 1, Target: 2, Type: 100},
+			// Retargeted
+			{ID: 11, Source: 1, Target: 2, Type: 100},
+			// Retyped
+			{ID: 12, Source: 2, Target: 3, Type: 101},
+			// Added, 13 is removed
+			{ID: 14, Source: 3, Target: 1, Type: 100},
+		},
+	}
+
+	diff := diffTopology(before, after)
+	if len(diff.AddedRelations) != 1 || diff.AddedRelations[0].ID != 14 {
+		t.Errorf("added relations = %+v, want 14", diff.AddedRelations)
+	}
+	if len(diff.RemovedRelations) != 1 || diff.RemovedRelations[0].ID != 13 {
+		t.Errorf("removed relations = %+v, want 13", diff.RemovedRelations)
+	}
+	if len(diff.ChangedRelations) != 2 || diff.ChangedRelations[0].After.ID != 11 || diff.ChangedRelations[1].After.ID != 12 {
+		t.Fatalf("changed relations = %+v, want 11 and 12", diff.ChangedRelations)
+	}
+	if c := diff.ChangedRelations[0]; c.Before.Target != "cart" || c.After.Target != "payment" {
+		t.Errorf("changed relation 11 = %+v, want its target to go from cart to payment", c)
+	}
+}
+
+func TestDiffTopologyRelationsFromComponents(t *testing.T) {
+	// Without relations in the snapshots, endpoints outside of the query result are unknown and not compared
+	before := &suseobservability.ViewSnapshotResponse{Components: []suseobservability.ViewComponent{
+		{ID: 1, Name: "checkout", OutgoingRelations: []int64{10, 11}},
+		{ID: 2, Name: "payment", IncomingRelations: []int64{10}},
+	}}
+	after := &suseobservability.ViewSnapshotResponse{Components: []suseobservability.ViewComponent{
+		{ID: 1, Name: "checkout", OutgoingRelations: []int64{10, 11}},
+		{ID: 3, Name: "cart", IncomingRelations: []int64{10}},
+	}}
+
+	diff := diffTopology(before, after)
+	if len(diff.AddedRelations) != 0 || len(diff.RemovedRelations) != 0 {
+		t.Errorf("added relations = %+v, removed relations = %+v, want none", diff.AddedRelations, diff.RemovedRelations)
+	}
+	if len(diff.ChangedRelations) != 1 || diff.ChangedRelations[0].After.ID != 10 {
+		t.Errorf("changed relations = %+v, want 10", diff.ChangedRelations)
+	}
+}
@@ -0,0 +1,491 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"suse-observability-mcp/client/suseobservability"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const defaultTracesPageSize = 20
+
+// spanLookups bounds the concurrent requests for the details of the spans a search found
+const spanLookups = 8
+
+type SearchTracesParams struct {
+	InstanceParams
+
+	ServiceNames string `json:"service_names,omitempty" jsonschema:"Service names to match (comma-separated, e.g., 'checkout,payment')"`
+	SpanNames    string `json:"span_names,omitempty" jsonschema:"Span names to match (comma-separated, e.g., 'GET /cart,SELECT')"`
+	SpanKinds    string `json:"span_kinds,omitempty" jsonschema:"Span kinds (comma-separated): 'server', 'client', 'producer', 'consumer', 'internal', 'unspecified'"`
+	StatusCodes  string `json:"status_codes,omitempty" jsonschema:"Span status codes (comma-separated): 'ok', 'error', 'unset'"`
+	MinDuration  string `json:"min_duration,omitempty" jsonschema:"Minimum span duration (e.g., '500ms', '2s')"`
+	MaxDuration  string `json:"max_duration,omitempty" jsonschema:"Maximum span duration (e.g., '10s')"`
+	Attributes   string `json:"attributes,omitempty" jsonschema:"Span attributes as key=value pairs (comma-separated, e.g., 'http.status_code=500,k8s.namespace.name=prod')"`
+	Start        string `json:"start,omitempty" jsonschema:"Start time: 'now', duration ago (e.g. '1h', '7d'), 'now-2h', 'today', 'yesterday', RFC3339 or Unix timestamp,default=1h"`
+	End          string `json:"end,omitempty" jsonschema:"End time: 'now', duration ago (e.g. '1h', '7d'), 'now-2h', 'today', 'yesterday', RFC3339 or Unix timestamp,default=now"`
+	SortBy       string `json:"sort_by,omitempty" jsonschema:"Sort order: 'start_time' (newest first) or 'duration' (slowest first),default=start_time"`
+	Page         int    `json:"page,omitempty" jsonschema:"Page number, starting at 0"`
+	PageSize     int    `json:"page_size,omitempty" jsonschema:"Number of spans per page (1-100),default=20"`
+}
+
+type GetTraceParams struct {
+	InstanceParams
+
+	TraceID string `json:"trace_id" jsonschema:"required,The ID of the trace to retrieve"`
+}
+
+type GetSpanParams struct {
+	InstanceParams
+
+	TraceID string `json:"trace_id" jsonschema:"required,The ID of the trace the span belongs to"`
+	SpanID  string `json:"span_id" jsonschema:"required,The ID of the span to retrieve"`
+}
+
+type SpanSummary struct {
+	TraceID      string    `json:"trace_id"`
+	SpanID       string    `json:"span_id"`
+	ParentSpanID string    `json:"parent_span_id,omitempty"`
+	Service      string    `json:"service,omitempty"`
+	Name         string    `json:"name,omitempty"`
+	Kind         string    `json:"kind,omitempty"`
+	Status       string    `json:"status,omitempty"`
+	Start        time.Time `json:"start,omitzero"`
+	DurationMs   float64   `json:"duration_ms"`
+}
+
+type SpanSearchResult struct {
+	Total    int           `json:"total"`
+	Page     int           `json:"page"`
+	PageSize int           `json:"page_size"`
+	Spans    []SpanSummary `json:"spans"`
+	// Errors are the spans whose details failed to load, only their IDs are in Spans
+	Errors []string `json:"errors,omitempty"`
+}
+
+// TraceSpan is a span of a trace listed in tree order, Depth being its distance to the root
+type TraceSpan struct {
+	SpanSummary
+	Depth int `json:"depth"`
+}
+
+type TraceResult struct {
+	TraceID    string      `json:"trace_id"`
+	Start      time.Time   `json:"start,omitzero"`
+	DurationMs float64     `json:"duration_ms"`
+	Errors     int         `json:"errors"`
+	Spans      []TraceSpan `json:"spans"`
+}
+
+type SpanEvent struct {
+	Time       time.Time         `json:"time"`
+	Name       string            `json:"name"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+type SpanDetails struct {
+	SpanSummary
+	ParentType         string            `json:"parent_type,omitempty"`
+	Scope              string            `json:"scope,omitempty"`
+	SpanAttributes     map[string]string `json:"span_attributes,omitempty"`
+	ResourceAttributes map[string]string `json:"resource_attributes,omitempty"`
+	Events             []SpanEvent       `json:"events,omitempty"`
+}
+
+// SearchTraces searches for spans matching the given filters
+func (t tool) SearchTraces(ctx context.Context, request *mcp.CallToolRequest, params SearchTracesParams) (*mcp.CallToolResult, *SpanSearchResult, error) {
+	t, err := t.instance(params.Instance)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	start, end, err := parseTimeRange(params.Start, params.End, t.windows["searchTraces"])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	filter, err := buildSpanFilter(params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sortBy := suseobservability.SortBy{Field: suseobservability.SpanSortStartTime, Direction: suseobservability.SortDirectionDescending}
+	switch params.SortBy {
+	case "", "start_time":
+	case "duration":
+		sortBy.Field = suseobservability.SpanSortDurationNanos
+	default:
+		return nil, nil, fmt.Errorf("invalid sort_by '%s'. Must be 'start_time' or 'duration'", params.SortBy)
+	}
+
+	pageSize := params.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultTracesPageSize
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	res, err := t.client.QueryTraces(ctx, &suseobservability.TraceQueryRequest{
+		TraceQuery: suseobservability.TraceQuery{
+			SpanFilter: filter,
+			SortBy:     []suseobservability.SortBy{sortBy},
+		},
+		Start:    start,
+		End:      end,
+		Page:     params.Page,
+		PageSize: pageSize,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query traces: %w", err)
+	}
+
+	result := &SpanSearchResult{Total: res.MatchesTotal, Page: res.Page, PageSize: res.PageSize, Spans: make([]SpanSummary, 0, len(res.Traces))}
+
+	if len(res.Traces) == 0 {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{
+					Text: "No spans found for the given filters.",
+				},
+			},
+		}, result, nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Found %d matching span(s), showing page %d (%d per page):\n\n", res.MatchesTotal, res.Page, res.PageSize))
+	sb.WriteString("| Trace ID | Span ID | Service | Span Name | Kind | Status | Start | Duration |\n")
+	sb.WriteString("|---|---|---|---|---|---|---|---|\n")
+
+	spans, errs := t.getSpans(ctx, res.Traces)
+	for n, ref := range res.Traces {
+		span := spans[n]
+		if errs[n] != nil {
+			sb.WriteString(fmt.Sprintf("| %s | %s | - | - | - | - | - | - |\n", ref.TraceID, ref.SpanID))
+			result.Spans = append(result.Spans, SpanSummary{TraceID: ref.TraceID, SpanID: ref.SpanID})
+			result.Errors = append(result.Errors, fmt.Sprintf("span %s of trace %s: %s", ref.SpanID, ref.TraceID, errs[n]))
+			continue
+		}
+		result.Spans = append(result.Spans, toSpanSummary(*span))
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s | %s | %s |\n",
+			ref.TraceID, ref.SpanID, span.ServiceName, span.SpanName, shortSpanKind(span.SpanKind),
+			span.StatusCode, spanTime(span.StartTime).Format(time.RFC3339), time.Duration(span.DurationNanos)))
+	}
+
+	if len(result.Errors) > 0 {
+		sb.WriteString(fmt.Sprintf("\nFailed to load the details of %d span(s), shown with '-':\n", len(result.Errors)))
+		for _, e := range result.Errors {
+			sb.WriteString(fmt.Sprintf("- %s\n", e))
+		}
+	}
+
+	if (res.Page+1)*res.PageSize < res.MatchesTotal {
+		sb.WriteString(fmt.Sprintf("\nMore results available, use page=%d to see the next page.\n", res.Page+1))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: sb.String(),
+			},
+		},
+	}, result, nil
+}
+
+// getSpans fetches the spans of the references concurrently, returning the spans and errors in the order of the references
+func (t tool) getSpans(ctx context.Context, refs []suseobservability.TraceRef) ([]*suseobservability.Span, []error) {
+	spans := make([]*suseobservability.Span, len(refs))
+	errs := make([]error, len(refs))
+	sem := make(chan struct{}, spanLookups)
+	var wg sync.WaitGroup
+	for n, ref := range refs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			spans[n], errs[n] = t.client.GetTraceSpan(ctx, ref.TraceID, ref.SpanID)
+		}()
+	}
+	wg.Wait()
+	return spans, errs
+}
+
+// GetTrace retrieves a trace and renders its spans as a tree
+func (t tool) GetTrace(ctx context.Context, request *mcp.CallToolRequest, params GetTraceParams) (*mcp.CallToolResult, *TraceResult, error) {
+	t, err := t.instance(params.Instance)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	trace, err := t.client.GetTrace(ctx, params.TraceID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get trace: %w", err)
+	}
+
+	text, result := formatTraceTree(trace)
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: text,
+			},
+		},
+	}, result, nil
+}
+
+// GetSpan retrieves a single span with all its attributes and events
+func (t tool) GetSpan(ctx context.Context, request *mcp.CallToolRequest, params GetSpanParams) (*mcp.CallToolResult, *SpanDetails, error) {
+	t, err := t.instance(params.Instance)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	span, err := t.client.GetTraceSpan(ctx, params.TraceID, params.SpanID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get span: %w", err)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Span '%s' of service '%s':\n\n", span.SpanName, span.ServiceName))
+	sb.WriteString("| Field | Value |\n")
+	sb.WriteString("|---|---|\n")
+	sb.WriteString(fmt.Sprintf("| Trace ID | %s |\n", span.TraceID))
+	sb.WriteString(fmt.Sprintf("| Span ID | %s |\n", span.SpanID))
+	parent := span.ParentSpanID
+	if parent == "" {
+		parent = "-"
+	}
+	sb.WriteString(fmt.Sprintf("| Parent Span ID | %s |\n", parent))
+	sb.WriteString(fmt.Sprintf("| Kind | %s |\n", shortSpanKind(span.SpanKind)))
+	sb.WriteString(fmt.Sprintf("| Parent Type | %s |\n", span.SpanParentType))
+	sb.WriteString(fmt.Sprintf("| Status | %s |\n", span.StatusCode))
+	sb.WriteString(fmt.Sprintf("| Start | %s |\n", spanTime(span.StartTime).Format(time.RFC3339Nano)))
+	sb.WriteString(fmt.Sprintf("| Duration | %s |\n", time.Duration(span.DurationNanos)))
+	sb.WriteString(fmt.Sprintf("| Scope | %s |\n", span.ScopeName))
+
+	writeAttributes(&sb, "Span Attributes", span.SpanAttributes)
+	writeAttributes(&sb, "Resource Attributes", span.ResourceAttributes)
+
+	if len(span.Events) > 0 {
+		sb.WriteString("\nEvents:\n\n")
+		sb.WriteString("| Time | Name | Attributes |\n")
+		sb.WriteString("|---|---|---|\n")
+		for _, e := range span.Events {
+			attrs := make([]string, 0, len(e.Attributes))
+			for _, k := range sortedAttributeKeys(e.Attributes) {
+				attrs = append(attrs, fmt.Sprintf("%s=%s", k, e.Attributes[k]))
+			}
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s |\n", spanTime(e.Timestamp).Format(time.RFC3339Nano), e.Name, strings.Join(attrs, ", ")))
+		}
+	}
+
+	details := &SpanDetails{
+		SpanSummary:        toSpanSummary(*span),
+		ParentType:         span.SpanParentType,
+		Scope:              span.ScopeName,
+		SpanAttributes:     span.SpanAttributes,
+		ResourceAttributes: span.ResourceAttributes,
+	}
+	for _, e := range span.Events {
+		details.Events = append(details.Events, SpanEvent{Time: spanTime(e.Timestamp), Name: e.Name, Attributes: e.Attributes})
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: sb.String(),
+			},
+		},
+	}, details, nil
+}
+
+func buildSpanFilter(params SearchTracesParams) (suseobservability.SpanFilter, error) {
+	filter := suseobservability.SpanFilter{
+		ServiceName: splitValues(params.ServiceNames),
+		SpanName:    splitValues(params.SpanNames),
+	}
+
+	for _, k := range splitValues(params.SpanKinds) {
+		kind := strings.ToUpper(k)
+		if !strings.HasPrefix(kind, "SPAN_KIND_") {
+			kind = "SPAN_KIND_" + kind
+		}
+		switch suseobservability.SpanKind(kind) {
+		case suseobservability.SpanKindClient, suseobservability.SpanKindServer,
+			suseobservability.SpanKindProducer, suseobservability.SpanKindConsumer,
+			suseobservability.SpanKindInternal, suseobservability.SpanKindUnspecified:
+			filter.SpanKind = append(filter.SpanKind, suseobservability.SpanKind(kind))
+		default:
+			return filter, fmt.Errorf("invalid span kind '%s'. Must be 'server', 'client', 'producer', 'consumer', 'internal' or 'unspecified'", k)
+		}
+	}
+
+	for _, s := range splitValues(params.StatusCodes) {
+		status := suseobservability.StatusCode(strings.ToLower(s))
+		switch status {
+		case suseobservability.StatusOk, suseobservability.StatusError, suseobservability.StatusUnset:
+			filter.StatusCode = append(filter.StatusCode, status)
+		default:
+			return filter, fmt.Errorf("invalid status code '%s'. Must be 'ok', 'error' or 'unset'", s)
+		}
+	}
+
+	if params.MinDuration != "" {
+		d, err := time.ParseDuration(params.MinDuration)
+		if err != nil {
+			return filter, fmt.Errorf("invalid min_duration: %w", err)
+		}
+		filter.DurationFromNanos = d.Nanoseconds()
+	}
+	if params.MaxDuration != "" {
+		d, err := time.ParseDuration(params.MaxDuration)
+		if err != nil {
+			return filter, fmt.Errorf("invalid max_duration: %w", err)
+		}
+		filter.DurationToNanos = d.Nanoseconds()
+	}
+
+	for _, a := range splitValues(params.Attributes) {
+		key, value, ok := strings.Cut(a, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return filter, fmt.Errorf("invalid attribute filter '%s'. Must be in the form key=value", a)
+		}
+		if filter.Attributes == nil {
+			filter.Attributes = make(suseobservability.FilterAttributes)
+		}
+		key = strings.TrimSpace(key)
+		filter.Attributes[key] = append(filter.Attributes[key], strings.TrimSpace(value))
+	}
+
+	return filter, nil
+}
+
+// formatTraceTree renders the spans as a tree, also returning them in the same order
+func formatTraceTree(trace *suseobservability.Trace) (string, *TraceResult) {
+	result := &TraceResult{TraceID: trace.TraceID, Spans: make([]TraceSpan, 0, len(trace.Spans))}
+	if len(trace.Spans) == 0 {
+		return fmt.Sprintf("No spans found for trace %s", trace.TraceID), result
+	}
+
+	// Index spans and group them by parent
+	known := make(map[string]bool, len(trace.Spans))
+	for _, s := range trace.Spans {
+		known[s.SpanID] = true
+	}
+	children := make(map[string][]suseobservability.Span)
+	var roots []suseobservability.Span
+	traceStart := spanTime(trace.Spans[0].StartTime)
+	traceEnd := spanTime(trace.Spans[0].EndTime)
+	errorCount := 0
+	for _, s := range trace.Spans {
+		if s.ParentSpanID == "" || !known[s.ParentSpanID] {
+			roots = append(roots, s)
+		} else {
+			children[s.ParentSpanID] = append(children[s.ParentSpanID], s)
+		}
+		if st := spanTime(s.StartTime); st.Before(traceStart) {
+			traceStart = st
+		}
+		if et := spanTime(s.EndTime); et.After(traceEnd) {
+			traceEnd = et
+		}
+		if s.StatusCode == string(suseobservability.StatusError) {
+			errorCount++
+		}
+	}
+
+	byStart := func(spans []suseobservability.Span) {
+		sort.SliceStable(spans, func(i, j int) bool {
+			return spanTime(spans[i].StartTime).Before(spanTime(spans[j].StartTime))
+		})
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Trace %s: %d span(s), %d error(s), total duration %s, started %s\n\n",
+		trace.TraceID, len(trace.Spans), errorCount, traceEnd.Sub(traceStart), traceStart.Format(time.RFC3339)))
+	sb.WriteString("```\n")
+
+	var walk func(s suseobservability.Span, depth int)
+	walk = func(s suseobservability.Span, depth int) {
+		marker := ""
+		if s.StatusCode == string(suseobservability.StatusError) {
+			marker = " [ERROR]"
+		}
+		sb.WriteString(fmt.Sprintf("%s- %s: %s (%s) %s +%s span=%s%s\n",
+			strings.Repeat("  ", depth), s.ServiceName, s.SpanName, shortSpanKind(s.SpanKind),
+			time.Duration(s.DurationNanos), spanTime(s.StartTime).Sub(traceStart), s.SpanID, marker))
+		result.Spans = append(result.Spans, TraceSpan{SpanSummary: toSpanSummary(s), Depth: depth})
+		kids := children[s.SpanID]
+		byStart(kids)
+		for _, c := range kids {
+			walk(c, depth+1)
+		}
+	}
+
+	byStart(roots)
+	for _, r := range roots {
+		walk(r, 0)
+	}
+	sb.WriteString("```\n")
+
+	result.Start = traceStart
+	result.DurationMs = durationMs(traceEnd.Sub(traceStart))
+	result.Errors = errorCount
+
+	return sb.String(), result
+}
+
+func toSpanSummary(s suseobservability.Span) SpanSummary {
+	return SpanSummary{
+		TraceID:      s.TraceID,
+		SpanID:       s.SpanID,
+		ParentSpanID: s.ParentSpanID,
+		Service:      s.ServiceName,
+		Name:         s.SpanName,
+		Kind:         shortSpanKind(s.SpanKind),
+		Status:       s.StatusCode,
+		Start:        spanTime(s.StartTime),
+		DurationMs:   durationMs(time.Duration(s.DurationNanos)),
+	}
+}
+
+func durationMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func writeAttributes(sb *strings.Builder, title string, attrs suseobservability.Attributes) {
+	if len(attrs) == 0 {
+		return
+	}
+	sb.WriteString(fmt.Sprintf("\n%s:\n\n", title))
+	sb.WriteString("| Key | Value |\n")
+	sb.WriteString("|---|---|\n")
+	for _, k := range sortedAttributeKeys(attrs) {
+		sb.WriteString(fmt.Sprintf("| %s | %s |\n", k, attrs[k]))
+	}
+}
+
+func sortedAttributeKeys(attrs suseobservability.Attributes) []string {
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// spanTime converts a span timestamp (milliseconds plus a nanosecond offset) to a time.Time
+func spanTime(st suseobservability.SpanTime) time.Time {
+	return time.UnixMilli(st.Timestamp).Add(time.Duration(st.OffsetNanos))
+}
+
+func shortSpanKind(kind string) string {
+	return strings.ToLower(strings.TrimPrefix(kind, "SPAN_KIND_"))
+}